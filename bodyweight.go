@@ -0,0 +1,122 @@
+package traindown
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ResolveRelativeLoads sets the Load of every Performance written relative to
+// bodyweight, such as "BW", "BW+20", or "1.5BW", from bw. Any offset is
+// added as written, without unit conversion. The first malformed expression
+// is returned as an error.
+func (s *Session) ResolveRelativeLoads(bw float32) error {
+	var err error
+	s.EachPerformance(func(m *Movement, p *Performance) {
+		if p.RelativeLoad == "" {
+			return
+		}
+
+		factor, offset, e := relativeLoad(p.RelativeLoad)
+		if e != nil {
+			if err == nil {
+				err = e
+			}
+			return
+		}
+
+		p.setLoad(factor*bw + offset)
+	})
+	return err
+}
+
+// RelativeStrength is the Load of the Performance as a multiple of
+// bodyweight, given in the same unit, or 0 without a bodyweight.
+func (p *Performance) RelativeStrength(bodyweight float32) float32 {
+	if bodyweight <= 0 {
+		return 0
+	}
+	return p.Load / bodyweight
+}
+
+// Wilks scores a powerlifting total against the lifter's bodyweight, both in
+// kilograms, using the original Wilks coefficients for men or women. It
+// returns 0 without a bodyweight.
+func Wilks(total float32, bodyweight float32, male bool) float32 {
+	if bodyweight <= 0 {
+		return 0
+	}
+
+	c := wilksFemale
+	if male {
+		c = wilksMale
+	}
+
+	x := float64(bodyweight)
+	var denom, pow float64 = 0, 1
+	for _, k := range c {
+		denom += k * pow
+		pow *= x
+	}
+
+	return float32(float64(total) * 500 / denom)
+}
+
+// Bodyweight reads the "bodyweight", "body weight", or "bw" metadata of the
+// Session in any casing, ignoring any unit after the number.
+func (s *Session) Bodyweight() (float32, bool) {
+	k, ok := s.Metadata.lookup("bodyweight", "body_weight", "bw")
+	if !ok {
+		return 0, false
+	}
+
+	if f, ok := s.Metadata.Float(k); ok {
+		return f, true
+	}
+
+	fields := strings.Fields(fmt.Sprint(s.Metadata[k]))
+	if len(fields) == 0 {
+		return 0, false
+	}
+
+	f, err := strconv.ParseFloat(strings.TrimRightFunc(fields[0], unicode.IsLetter), 32)
+	return float32(f), err == nil
+}
+
+/* Private */
+
+// The Wilks polynomial coefficients, from the constant term up.
+var (
+	wilksMale   = []float64{-216.0475144, 16.2606339, -0.002388645, -0.00113732, 7.01863e-06, -1.291e-08}
+	wilksFemale = []float64{594.31747775582, -27.23842536447, 0.82112226871, -0.00930733913, 4.731582e-05, -9.054e-08}
+)
+
+// relativeLoad splits an expression such as "1.5BW+20" into its factor of
+// bodyweight and its offset.
+func relativeLoad(expr string) (float32, float32, error) {
+	i := strings.Index(expr, "BW")
+	if i < 0 {
+		return 0, 0, fmt.Errorf("Failed to parse %q: %q", "relative load", expr)
+	}
+
+	factor := float32(1)
+	if i > 0 {
+		f, err := floatValue(expr[:i], "relative load")
+		if err != nil {
+			return 0, 0, err
+		}
+		factor = f
+	}
+
+	var offset float32
+	if rest := expr[i+2:]; rest != "" {
+		f, err := floatValue(strings.TrimPrefix(rest, "+"), "relative load")
+		if err != nil {
+			return 0, 0, err
+		}
+		offset = f
+	}
+
+	return factor, offset, nil
+}
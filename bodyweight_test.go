@@ -0,0 +1,129 @@
+package traindown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseRelativeLoads(t *testing.T) {
+	text := `
+    # bodyweight: 80 kg
+
+    pullups:
+      BW 10r
+      BW+10 5r
+      bw + 20kg 3r
+      1.25BW 3r
+      1.5 bw 1r
+      BW-10 8r`
+
+	session, err := ParseString(text)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	expected := []struct {
+		rel  string
+		load float32
+	}{
+		{"BW", 80},
+		{"BW+10", 90},
+		{"BW+20", 100},
+		{"1.25BW", 100},
+		{"1.5BW", 120},
+		{"BW-10", 70},
+	}
+
+	ps := session.Movements[0].Performances
+
+	if len(ps) != len(expected) || len(session.Errors) != 0 {
+		t.Fatalf("Expected %d performances, got %v %v", len(expected), ps, session.Errors)
+	}
+
+	for i, ex := range expected {
+		if ps[i].RelativeLoad != ex.rel || ps[i].Load != ex.load {
+			t.Errorf("Expected %q at %v, got %q at %v", ex.rel, ex.load, ps[i].RelativeLoad, ps[i].Load)
+		}
+	}
+
+	if ps[2].Unit != "kg" {
+		t.Errorf("Expected the offset unit, got %q", ps[2].Unit)
+	}
+
+	session, err = ParseString(strings.Replace(text, "# bodyweight: 80 kg", "", 1))
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	for _, p := range session.Movements[0].Performances {
+		if p.Load != 0 || p.RelativeLoad == "" {
+			t.Errorf("Expected an unresolved relative load: %v", p)
+		}
+	}
+
+	if err = session.ResolveRelativeLoads(100); err != nil || session.Movements[0].Performances[3].Load != 125 {
+		t.Errorf("Failed to resolve later: %v %v", err, session.Movements[0].Performances[3])
+	}
+
+	b, err := session.Marshal()
+
+	if err != nil || !strings.Contains(string(b), "  1.25BW 3r\n") {
+		t.Errorf("Relative load not preserved:\n%s", b)
+	}
+}
+
+func TestRelativeStrength(t *testing.T) {
+	p := &Performance{Load: 200}
+
+	if r := p.RelativeStrength(80); r != 2.5 {
+		t.Errorf("Unexpected relative strength: %v", r)
+	}
+
+	if r := p.RelativeStrength(0); r != 0 {
+		t.Errorf("Expected 0 without a bodyweight, got %v", r)
+	}
+}
+
+func TestWilks(t *testing.T) {
+	cases := []struct {
+		total      float32
+		bodyweight float32
+		male       bool
+		expected   float32
+	}{
+		{600, 90, true, 383.04},
+		{400, 60, false, 445.95},
+		{600, 0, true, 0},
+	}
+
+	for _, c := range cases {
+		w := Wilks(c.total, c.bodyweight, c.male)
+
+		if w < c.expected-0.01 || w > c.expected+0.01 {
+			t.Errorf("Unexpected Wilks for %v at %v: %v", c.total, c.bodyweight, w)
+		}
+	}
+}
+
+func TestSessionBodyweight(t *testing.T) {
+	cases := map[string]float32{
+		"# Body Weight: 82.5kg\n": 82.5,
+		"# BW: 180\n":             180,
+		"# mood: great\n":         0,
+	}
+
+	for meta, want := range cases {
+		session, err := ParseString("@ 2021-03-04\n" + meta + "squat:\n  100 5r\n")
+
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %q", meta, err)
+		}
+
+		bw, ok := session.Bodyweight()
+		if bw != want || ok != (want != 0) {
+			t.Errorf("Unexpected bodyweight for %q: %v %v", meta, bw, ok)
+		}
+	}
+}
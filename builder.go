@@ -0,0 +1,164 @@
+package traindown
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SessionBuilder assembles a Session in code, much like writing it out in
+// Traindown:
+//
+//	s, err := NewSessionBuilder().
+//		Date(d).
+//		Movement("Squat").
+//		Perform(225, 5, 3).
+//		Note("felt good").
+//		Build()
+//
+// Note and Meta attach to the most recent performance, movement, or the
+// session itself, in that order. As with parsing, Metadata is left nil until
+// metadata is given.
+type SessionBuilder struct {
+	s    *Session
+	m    *Movement
+	p    *Performance
+	errs []error
+}
+
+// BuildErrors aggregates the problems found by SessionBuilder.Build.
+type BuildErrors []error
+
+func (e BuildErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d build error(s): %s", len(e), strings.Join(msgs, "; "))
+}
+
+/* Public */
+
+// NewSessionBuilder returns a SessionBuilder for an empty Session.
+func NewSessionBuilder() *SessionBuilder {
+	return &SessionBuilder{s: NewSession()}
+}
+
+// Date sets the date of the Session.
+func (b *SessionBuilder) Date(d time.Time) *SessionBuilder {
+	b.s.Date = d
+	return b
+}
+
+// Unit sets the default unit of the current movement, or of the Session if no
+// movement has been started.
+func (b *SessionBuilder) Unit(u string) *SessionBuilder {
+	if b.m != nil {
+		b.m.DefaultUnit = u
+	} else {
+		b.s.DefaultUnit = u
+	}
+	return b
+}
+
+// Movement starts a new Movement.
+func (b *SessionBuilder) Movement(name string) *SessionBuilder {
+	return b.movement(name, false)
+}
+
+// SuperSet starts a new Movement supersetted with the previous one.
+func (b *SessionBuilder) SuperSet(name string) *SessionBuilder {
+	return b.movement(name, true)
+}
+
+// Perform adds a Performance to the current Movement.
+func (b *SessionBuilder) Perform(load float32, reps int, sets int) *SessionBuilder {
+	if b.m == nil {
+		b.errs = append(b.errs, fmt.Errorf("Performance of %v given before any movement", load))
+		return b
+	}
+
+	b.p = newPerformance()
+	b.p.Load, b.p.LoadMin, b.p.LoadMax = load, load, load
+	b.p.Reps = reps
+	b.p.Sets = sets
+	b.p.Sequence = len(b.m.Performances) + 1
+	b.m.Performances = append(b.m.Performances, b.p)
+	return b
+}
+
+// Note adds a note to the most recent element.
+func (b *SessionBuilder) Note(n string) *SessionBuilder {
+	switch {
+	case b.p != nil:
+		b.p.Notes = append(b.p.Notes, n)
+	case b.m != nil:
+		b.m.Notes = append(b.m.Notes, n)
+	default:
+		b.s.Notes = append(b.s.Notes, n)
+	}
+	return b
+}
+
+// Meta sets a metadata pair on the most recent element.
+func (b *SessionBuilder) Meta(k string, v interface{}) *SessionBuilder {
+	md := &b.s.Metadata
+	switch {
+	case b.p != nil:
+		md = &b.p.Metadata
+	case b.m != nil:
+		md = &b.m.Metadata
+	}
+
+	md.Set(k, v)
+	return b
+}
+
+// Build returns the assembled Session, inheriting units as the parser would.
+// A BuildErrors error is returned when the Session is undated, a movement is
+// unnamed or empty, or a performance has no reps or sets.
+func (b *SessionBuilder) Build() (*Session, error) {
+	errs := append([]error{}, b.errs...)
+
+	if b.s.Date.IsZero() {
+		errs = append(errs, fmt.Errorf("Missing session date"))
+	}
+
+	for _, m := range b.s.Movements {
+		if strings.TrimSpace(m.Name) == "" {
+			errs = append(errs, fmt.Errorf("Movement #%d: Missing name", m.Sequence))
+		}
+
+		if len(m.Performances) == 0 {
+			errs = append(errs, fmt.Errorf("%s: No performances", m.Name))
+		}
+
+		for _, p := range m.Performances {
+			if p.Reps < 1 {
+				errs = append(errs, fmt.Errorf("%s #%d: Missing reps", m.Name, p.Sequence))
+			}
+			if p.Sets < 1 {
+				errs = append(errs, fmt.Errorf("%s #%d: Missing sets", m.Name, p.Sequence))
+			}
+			p.maybeInheritUnit(b.s, m)
+		}
+	}
+
+	if len(errs) > 0 {
+		return b.s, BuildErrors(errs)
+	}
+
+	return b.s, nil
+}
+
+/* Private */
+
+func (b *SessionBuilder) movement(name string, superSet bool) *SessionBuilder {
+	b.m = newMovement()
+	b.m.Name = name
+	b.m.SuperSet = superSet
+	b.m.Sequence = len(b.s.Movements) + 1
+	b.p = nil
+	b.s.Movements = append(b.s.Movements, b.m)
+	return b
+}
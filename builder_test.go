@@ -0,0 +1,80 @@
+package traindown
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSessionBuilder(t *testing.T) {
+	d := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	built, err := NewSessionBuilder().
+		Date(d).
+		Unit("lb").
+		Meta("bw", "180").
+		Note("session note").
+		Movement("squat").
+		Perform(225, 5, 3).
+		Note("felt good").
+		Movement("bench").
+		Meta("grip", "wide").
+		Perform(135, 10, 1).
+		SuperSet("row").
+		Unit("kg").
+		Perform(60, 8, 1).
+		Build()
+
+	if err != nil {
+		t.Fatalf("Failed to build: %q", err)
+	}
+
+	parsed, err := ParseString(`
+    @ 2020-01-01
+    # unit: lb
+    # bw: 180
+    * session note
+
+    squat:
+      225 5r 3s
+        * felt good
+
+    bench:
+      # grip: wide
+      135 10r
+
+    + row:
+      # unit: kg
+      60 8r`)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if !reflect.DeepEqual(built, parsed) {
+		t.Errorf("Built session mismatch.\n\nGot:\n%v\n\nExpected:\n%v", built, parsed)
+	}
+}
+
+func TestSessionBuilderErrors(t *testing.T) {
+	s, err := NewSessionBuilder().
+		Perform(100, 5, 1).
+		Movement("").
+		Perform(100, 0, 1).
+		Movement("empty").
+		Build()
+
+	errs, ok := err.(BuildErrors)
+
+	if !ok {
+		t.Fatalf("Expected BuildErrors, got %v", err)
+	}
+
+	if len(errs) != 5 {
+		t.Errorf("Expected 5 errors, got %d: %q", len(errs), errs)
+	}
+
+	if s == nil || len(s.Movements) != 2 {
+		t.Errorf("Expected the partial session, got %v", s)
+	}
+}
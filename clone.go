@@ -0,0 +1,110 @@
+package traindown
+
+// Clone returns a deep copy of the Session.
+func (s *Session) Clone() *Session {
+	c := *s
+	c.Errors = cloneErrors(s.Errors)
+	c.Metadata = s.Metadata.clone()
+	c.Notes = cloneNotes(s.Notes)
+
+	if s.Movements != nil {
+		c.Movements = make([]*Movement, len(s.Movements))
+		for i, m := range s.Movements {
+			c.Movements[i] = m.Clone()
+		}
+	}
+
+	return &c
+}
+
+// Clone returns a deep copy of the Movement.
+func (m *Movement) Clone() *Movement {
+	c := *m
+	c.Metadata = m.Metadata.clone()
+	c.Notes = cloneNotes(m.Notes)
+	c.Span = m.Span.clone()
+
+	if m.Performances != nil {
+		c.Performances = make([]*Performance, len(m.Performances))
+		for i, p := range m.Performances {
+			c.Performances[i] = p.Clone()
+		}
+	}
+
+	return &c
+}
+
+// Clone returns a deep copy of the Performance.
+func (p *Performance) Clone() *Performance {
+	c := *p
+	c.Metadata = p.Metadata.clone()
+	c.Notes = cloneNotes(p.Notes)
+	c.Span = p.Span.clone()
+
+	if p.SetDetails != nil {
+		c.SetDetails = make([]*Set, len(p.SetDetails))
+		for i, s := range p.SetDetails {
+			c.SetDetails[i] = s.Clone()
+		}
+	}
+
+	if p.LoadComponents != nil {
+		c.LoadComponents = append([]LoadComponent{}, p.LoadComponents...)
+	}
+
+	if p.LoadModifiers != nil {
+		c.LoadModifiers = append([]LoadModifier{}, p.LoadModifiers...)
+	}
+
+	if p.PerSetReps != nil {
+		c.PerSetReps = append([]int{}, p.PerSetReps...)
+	}
+
+	if p.RepRange != nil {
+		r := *p.RepRange
+		c.RepRange = &r
+	}
+
+	if p.Tempo != nil {
+		t := *p.Tempo
+		c.Tempo = &t
+	}
+
+	return &c
+}
+
+/* Private */
+
+func (m Metadata) clone() Metadata {
+	if m == nil {
+		return nil
+	}
+
+	c := make(Metadata, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+func (s *SourceSpan) clone() *SourceSpan {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	return &c
+}
+
+func cloneErrors(errs []error) []error {
+	if errs == nil {
+		return nil
+	}
+	return append(make([]error, 0, len(errs)), errs...)
+}
+
+func cloneNotes(notes []string) []string {
+	if notes == nil {
+		return nil
+	}
+	return append(make([]string, 0, len(notes)), notes...)
+}
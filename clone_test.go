@@ -0,0 +1,67 @@
+package traindown
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCloneSession(t *testing.T) {
+	text := `
+    @ 2020-01-01
+    # mood: good
+    * session note
+    squat:
+      # bar: ss yoke
+      100 5r
+        # tempo: 3-1-2-0
+        * performance note`
+
+	s, err := ParseString(text)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	c := s.Clone()
+
+	if !reflect.DeepEqual(s, c) {
+		t.Fatalf("Clone mismatch.\n\nGot:\n%v\n\nExpected:\n%v", c, s)
+	}
+
+	c.Metadata["mood"] = "bad"
+	c.Notes[0] = "changed"
+	c.Movements[0].Name = "bench"
+	c.Movements[0].Metadata["bar"] = "straight"
+	c.Movements[0].Performances[0].Load = 200
+	c.Movements[0].Performances[0].Metadata["tempo"] = "fast"
+	c.Movements[0].Performances[0].Tempo.Eccentric = 5
+	c.Movements[0].Performances[0].Notes[0] = "changed"
+	c.Movements = append(c.Movements, NewMovement())
+
+	m := s.Movements[0]
+	p := m.Performances[0]
+
+	if s.Metadata["mood"] != "good" ||
+		s.Notes[0] != "session note" ||
+		len(s.Movements) != 1 ||
+		m.Name != "squat" ||
+		m.Metadata["bar"] != "ss yoke" ||
+		p.Load != 100 ||
+		p.Metadata["tempo"] != "3-1-2-0" ||
+		p.Tempo.Eccentric != 3 ||
+		p.Notes[0] != "performance note" {
+		t.Errorf("Mutating the clone changed the original: %v", s)
+	}
+}
+
+func TestCloneEmpty(t *testing.T) {
+	s := &Session{}
+	if c := s.Clone(); !reflect.DeepEqual(s, c) {
+		t.Errorf("Clone mismatch: %v", c)
+	}
+
+	p := &Performance{}
+	if c := p.Clone(); !reflect.DeepEqual(p, c) {
+		t.Errorf("Clone mismatch: %v", c)
+	}
+}
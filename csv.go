@@ -0,0 +1,69 @@
+package traindown
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSVHeader lists the columns written by WriteCSV.
+var CSVHeader = []string{
+	"date", "movement", "superSet", "sequence", "load", "unit", "reps", "sets",
+	"fails", "percentOfMax", "metadata", "notes",
+}
+
+// WriteCSV writes a header and then one row per Performance to w. Metadata is
+// collapsed into sorted "key=value" pairs and notes are joined, both with "; ".
+func (s Session) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(CSVHeader); err != nil {
+		return err
+	}
+
+	date := ""
+	if !s.Date.IsZero() {
+		date = s.Date.Format(time.RFC3339)
+	}
+
+	for _, m := range s.Movements {
+		for _, p := range m.Performances {
+			row := []string{
+				date,
+				m.Name,
+				strconv.FormatBool(m.SuperSet),
+				strconv.Itoa(p.Sequence),
+				formatLoad(p.Load),
+				p.Unit,
+				strconv.Itoa(p.Reps),
+				strconv.Itoa(p.Sets),
+				strconv.Itoa(p.Fails),
+				formatLoad(p.PercentOfMax),
+				collapseMetadata(p.Metadata),
+				strings.Join(p.Notes, "; "),
+			}
+
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+/* Private */
+
+func collapseMetadata(md Metadata) string {
+	pairs := make([]string, 0, len(md))
+	for k, v := range md {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", k, v))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "; ")
+}
@@ -0,0 +1,60 @@
+package traindown
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWriteCSV(t *testing.T) {
+	text := `
+    @ 2020-01-01
+    squat:
+      100kg 5r 3s 1f
+        # rpe: 8
+        # bar: ss yoke
+        * felt "heavy", slow
+    + bench:
+      60 lb`
+
+	session, err := ParseString(text)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	var buf bytes.Buffer
+	if err = session.WriteCSV(&buf); err != nil {
+		t.Fatalf("Failed to write CSV: %q", err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+
+	if err != nil {
+		t.Fatalf("Failed to read CSV: %q", err)
+	}
+
+	expected := [][]string{
+		CSVHeader,
+		{"2020-01-01T00:00:00Z", "squat", "false", "1", "100", "kg", "5", "3", "1", "0", "bar=ss yoke; rpe=8", `felt "heavy", slow`},
+		{"2020-01-01T00:00:00Z", "bench", "true", "1", "60", "lb", "1", "1", "0", "0", "", ""},
+	}
+
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Output mismatch:\n\nGot:\n%q\n\nExpected:\n%q", rows, expected)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("your mom")
+}
+
+func TestWriteCSVError(t *testing.T) {
+	if err := NewSession().WriteCSV(failingWriter{}); err == nil {
+		t.Errorf("Expected writer error to propagate")
+	}
+}
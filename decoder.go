@@ -0,0 +1,136 @@
+package traindown
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/timtadh/lexmachine/machines"
+)
+
+// Decoder reads successive Sessions from a stream, much like json.Decoder.
+// A Session ends where a date follows its movements, so only one Session's
+// worth of input is held in memory at a time. Positions in errors count from
+// the start of the stream. An alias applies to its own Session and those
+// after it, but not to Sessions already decoded.
+type Decoder struct {
+	r   *bufio.Reader
+	cfg parseConfig
+	l   *Lexer
+	err error
+
+	// tokens are those of the Session being read, and moved reports whether
+	// they hold a movement.
+	tokens []*Token
+	moved  bool
+	// aliases are those defined so far, which apply to the Sessions after
+	// them.
+	aliases map[string]string
+	// queue holds the Sessions parsed but not yet returned.
+	queue []*Session
+	// lines and bytes count the input read so far.
+	lines int
+	bytes int
+}
+
+// NewDecoder returns a Decoder reading from r, parsing with opts.
+func NewDecoder(r io.Reader, opts ...Option) *Decoder {
+	return &Decoder{
+		r:       bufio.NewReader(r),
+		cfg:     newParseConfig(opts),
+		aliases: make(map[string]string),
+	}
+}
+
+// Decode returns the next Session in the stream, or io.EOF once the stream
+// is exhausted. In strict mode a Session with errors is returned alongside a
+// ParseErrors error.
+func (d *Decoder) Decode() (*Session, error) {
+	if d.l == nil {
+		l, err := NewLexer()
+		if err != nil {
+			return nil, err
+		}
+		d.l = &l
+	}
+
+	for len(d.queue) == 0 {
+		if d.err != nil {
+			if isEmpty(d.tokens) {
+				return nil, io.EOF
+			}
+			if err := d.parse(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		var line string
+		line, d.err = d.r.ReadString('\n')
+
+		if d.err != nil && d.err != io.EOF {
+			return nil, d.err
+		}
+
+		if err := d.scan(line); err != nil {
+			return nil, err
+		}
+	}
+
+	s := d.queue[0]
+	d.queue = d.queue[1:]
+
+	if d.cfg.strict && len(s.Errors) > 0 {
+		return s, ParseErrors(s.Errors)
+	}
+	return s, nil
+}
+
+/* Private */
+
+// scan lexes a line of input, parsing the Session before it when it dates a
+// new one.
+func (d *Decoder) scan(line string) error {
+	tokens, err := d.l.Scan([]byte(line))
+
+	if err != nil {
+		if ui, ok := err.(*machines.UnconsumedInput); ok {
+			ui.StartLine += d.lines
+			ui.FailLine += d.lines
+		}
+		return err
+	}
+
+	for _, tok := range tokens {
+		tok.t.StartLine += d.lines
+		tok.t.EndLine += d.lines
+		tok.t.TC += d.bytes
+
+		if tok.Name() == "DATE" && d.moved {
+			if err := d.parse(); err != nil {
+				return err
+			}
+		}
+
+		d.tokens = append(d.tokens, tok)
+		d.moved = d.moved || tok.Name() == "MOVEMENT" || tok.Name() == "MOVEMENT_SS"
+	}
+
+	d.lines++
+	d.bytes += len(line)
+	return nil
+}
+
+// parse queues the Sessions of the tokens read so far. Errors under strict
+// mode are left for Decode to report with each Session.
+func (d *Decoder) parse() error {
+	sessions, err := parseAliasedTokens(d.tokens, true, d.cfg, d.aliases)
+
+	if _, ok := err.(ParseErrors); err != nil && !ok {
+		return err
+	}
+
+	d.queue = append(d.queue, sessions...)
+	d.tokens = nil
+	d.moved = false
+	return nil
+}
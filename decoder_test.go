@@ -0,0 +1,116 @@
+package traindown
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecoder(t *testing.T) {
+	text := `@ 2020-01-01
+* first
+squat:
+  100 5r
+
+@ 2020-01-02
+@ 2020-01-03
+bench:
+  200 3r
++ row:
+  150
+
+@ 2020-01-04
+// a rest day
+
+`
+
+	d := NewDecoder(strings.NewReader(text))
+
+	expected := []struct {
+		date      time.Time
+		movements int
+	}{
+		{time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 1},
+		{time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC), 2},
+		{time.Date(2020, 1, 4, 0, 0, 0, 0, time.UTC), 0},
+	}
+
+	for i, ex := range expected {
+		s, err := d.Decode()
+
+		if err != nil {
+			t.Fatalf("Failed to decode session %d: %v", i, err)
+		}
+
+		if s.Date != ex.date || len(s.Movements) != ex.movements {
+			t.Errorf("Incorrect session %d: %v", i, s)
+		}
+	}
+
+	if _, err := d.Decode(); err != io.EOF {
+		t.Errorf("Expected io.EOF, got %v", err)
+	}
+
+	if _, err := d.Decode(); err != io.EOF {
+		t.Errorf("Expected io.EOF again, got %v", err)
+	}
+}
+
+func TestDecoderEmpty(t *testing.T) {
+	for _, text := range []string{"", "\n  \n"} {
+		if _, err := NewDecoder(strings.NewReader(text)).Decode(); err != io.EOF {
+			t.Errorf("Expected io.EOF for %q, got %v", text, err)
+		}
+	}
+}
+
+func TestDecoderStrict(t *testing.T) {
+	d := NewDecoder(strings.NewReader("@ nope\nsquat:\n  100\n@ 2020-01-01\nbench:\n  100"), WithStrict(true))
+
+	s, err := d.Decode()
+
+	if _, ok := err.(ParseErrors); !ok || s == nil || len(s.Errors) != 1 {
+		t.Errorf("Expected ParseErrors with the session, got %v (%v)", err, s)
+	}
+
+	if s, err = d.Decode(); err != nil || s.Movements[0].Name != "bench" {
+		t.Errorf("Failed to decode the second session: %v (%v)", s, err)
+	}
+}
+
+func TestDecoderMidLineDate(t *testing.T) {
+	d := NewDecoder(strings.NewReader("@ 2020-01-01\nsquat:\n  100 5r @ 2020-01-02\nbench:\n  200 3r\n  5.5r\n"))
+
+	s, err := d.Decode()
+
+	if err != nil || len(s.Movements) != 1 || s.Movements[0].Name != "squat" {
+		t.Fatalf("Failed to decode the first session: %v (%v)", s, err)
+	}
+
+	s, err = d.Decode()
+
+	if err != nil || s.Date != time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC) || len(s.Movements) != 1 {
+		t.Fatalf("Failed to decode the mid-line session: %v (%v)", s, err)
+	}
+
+	if len(s.Errors) != 1 || !strings.HasPrefix(s.Errors[0].Error(), "6:3: ") {
+		t.Errorf("Expected an error positioned in the stream, got %v", s.Errors)
+	}
+
+	if _, err := d.Decode(); err != io.EOF {
+		t.Errorf("Expected io.EOF, got %v", err)
+	}
+}
+
+func TestDecoderAliases(t *testing.T) {
+	d := NewDecoder(strings.NewReader("alias bp = Bench Press\n@ 2020-01-01\nbp:\n  100 5r\n@ 2020-01-02\nbp:\n  105 5r\n"))
+
+	for i := 0; i < 2; i++ {
+		s, err := d.Decode()
+
+		if err != nil || len(s.Movements) != 1 || s.Movements[0].Name != "Bench Press" {
+			t.Fatalf("Failed to expand the alias in session %d: %v (%v)", i, s, err)
+		}
+	}
+}
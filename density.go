@@ -0,0 +1,126 @@
+package traindown
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/araddon/dateparse"
+)
+
+// clockLayouts are the time of day formats accepted by Density.
+var clockLayouts = []string{"15:04", "15:04:05", "3:04pm", "3:04 pm", "3pm"}
+
+// Density is the Volume of the Session per minute between its "start" and
+// "end" metadata, given as times of day such as "10:00" or "6:30pm". An end
+// before the start is taken to be past midnight. An error is returned when
+// either time is missing or malformed, or no time elapsed.
+func (s *Session) Density() (float32, error) {
+	start, err := s.clock("start")
+	if err != nil {
+		return 0, err
+	}
+
+	end, err := s.clock("end")
+	if err != nil {
+		return 0, err
+	}
+
+	elapsed := end.Sub(start)
+	if elapsed < 0 {
+		elapsed += 24 * time.Hour
+	}
+
+	if elapsed == 0 {
+		return 0, fmt.Errorf("No time elapsed between start and end")
+	}
+
+	v, _ := s.Volume()
+	return v / float32(elapsed.Minutes()), nil
+}
+
+// AverageRest is the mean gap between the "time" metadata of consecutive
+// performances, skipping performances without one. Each gap runs from the
+// start of one performance to the next, so it includes the work of the
+// earlier one. Times are read as times of day, as in Density, before falling
+// back to dateparse for full timestamps; a gap between times of day that goes
+// backwards is taken to be past midnight. An error is returned when fewer
+// than two times are given, any is malformed, times of day are mixed with
+// timestamps, or timestamps go backwards.
+func (m *Movement) AverageRest() (time.Duration, error) {
+	var stamps []time.Time
+	var clocks int
+
+	for _, p := range m.Performances {
+		k, ok := p.Metadata.lookup("time")
+		if !ok {
+			continue
+		}
+
+		v, _ := p.Metadata.String(k)
+
+		if t, ok := parseClock(v); ok {
+			stamps = append(stamps, t)
+			clocks++
+			continue
+		}
+
+		t, err := dateparse.ParseAny(strings.TrimSpace(v))
+		if err != nil {
+			return 0, fmt.Errorf("%s #%d: Failed to parse %q: %q", m.Name, p.Sequence, "time", v)
+		}
+		stamps = append(stamps, t)
+	}
+
+	if len(stamps) < 2 {
+		return 0, fmt.Errorf("%s: Fewer than two times", m.Name)
+	}
+
+	if clocks > 0 && clocks < len(stamps) {
+		return 0, fmt.Errorf("%s: Times mix times of day and timestamps", m.Name)
+	}
+
+	var total time.Duration
+	for i := 1; i < len(stamps); i++ {
+		gap := stamps[i].Sub(stamps[i-1])
+		if gap < 0 {
+			if clocks == 0 {
+				return 0, fmt.Errorf("%s: Times out of order", m.Name)
+			}
+			gap += 24 * time.Hour
+		}
+		total += gap
+	}
+
+	return total / time.Duration(len(stamps)-1), nil
+}
+
+/* Private */
+
+func (s *Session) clock(name string) (time.Time, error) {
+	k, ok := s.Metadata.lookup(name)
+	if !ok {
+		return time.Time{}, fmt.Errorf("Missing %s time", name)
+	}
+
+	v, _ := s.Metadata.String(k)
+
+	if t, ok := parseClock(v); ok {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("Failed to parse %q: %q", name, strings.TrimSpace(v))
+}
+
+// parseClock reads a time of day in one of the clockLayouts.
+func parseClock(v string) (time.Time, bool) {
+	v = strings.ToLower(strings.TrimSpace(v))
+
+	for _, layout := range clockLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
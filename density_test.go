@@ -0,0 +1,87 @@
+package traindown
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDensity(t *testing.T) {
+	cases := []struct {
+		start    string
+		end      string
+		expected float32
+	}{
+		{"10:00", "11:30", 10},
+		{"23:30", "00:15", 20},
+		{"6:00pm", "6:45 PM", 20},
+	}
+
+	for _, c := range cases {
+		session, err := ParseString("# Start: " + c.start + "\n# end: " + c.end + "\nsquat:\n  100 3r 3s")
+
+		if err != nil {
+			t.Fatalf("Failed to parse: %q", err)
+		}
+
+		// 900 volume
+		d, err := session.Density()
+
+		if err != nil || d != c.expected {
+			t.Errorf("Expected a density of %v from %s to %s, got %v (%v)", c.expected, c.start, c.end, d, err)
+		}
+	}
+
+	for _, text := range []string{
+		"# start: 10:00\nsquat:\n  100",
+		"# start: 10:00\n# end: later\nsquat:\n  100",
+		"# start: 10:00\n# end: 10:00\nsquat:\n  100",
+	} {
+		session, err := ParseString(text)
+
+		if err != nil {
+			t.Fatalf("Failed to parse: %q", err)
+		}
+
+		if _, err := session.Density(); err == nil {
+			t.Errorf("Expected an error for %q", text)
+		}
+	}
+}
+
+func TestAverageRest(t *testing.T) {
+	cases := []struct {
+		times    []string
+		expected time.Duration
+		ok       bool
+	}{
+		{[]string{"10:00", "10:02", "", "10:06:30"}, 195 * time.Second, true},
+		{[]string{"11:59pm", "12:03am"}, 4 * time.Minute, true},
+		{[]string{"2020-01-01 10:00:00", "2020-01-01 10:03:00"}, 3 * time.Minute, true},
+		{[]string{"10:00"}, 0, false},
+		{[]string{"10:00", "later"}, 0, false},
+		{[]string{"10:00", "2020-01-01 10:03:00"}, 0, false},
+		{[]string{"2020-01-01 10:03:00", "2020-01-01 10:00:00"}, 0, false},
+	}
+
+	for _, c := range cases {
+		text := "squat:\n"
+		for _, ts := range c.times {
+			text += "  100 5r\n"
+			if ts != "" {
+				text += "    # time: " + ts + "\n"
+			}
+		}
+
+		session, err := ParseString(text)
+
+		if err != nil {
+			t.Fatalf("Failed to parse: %q", err)
+		}
+
+		d, err := session.Movements[0].AverageRest()
+
+		if (err == nil) != c.ok || d != c.expected {
+			t.Errorf("Unexpected rest for %v: %v (%v)", c.times, d, err)
+		}
+	}
+}
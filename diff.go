@@ -0,0 +1,95 @@
+package traindown
+
+// SessionDiff describes how one Session differs from another.
+type SessionDiff struct {
+	Added   []string       `json:"added,omitempty"`
+	Removed []string       `json:"removed,omitempty"`
+	Changed []MovementDiff `json:"changed,omitempty"`
+}
+
+// MovementDiff lists the performances of a Movement that differ between two
+// sessions.
+type MovementDiff struct {
+	Name         string            `json:"name"`
+	Performances []PerformanceDiff `json:"performances"`
+}
+
+// PerformanceDiff compares the performances found at Index in each session.
+// Before is nil for a Performance only in the later session, and After is nil
+// for one only in the earlier session.
+type PerformanceDiff struct {
+	Index  int          `json:"index"`
+	Before *Performance `json:"before,omitempty"`
+	After  *Performance `json:"after,omitempty"`
+}
+
+// Empty reports whether the sessions compared were alike.
+func (d SessionDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffSessions compares a to the later b. Movements are paired by name as in
+// MovementsByName, in order of appearance, and their performances are paired
+// by index. Only differences in load, reps, and sets are reported.
+func DiffSessions(a *Session, b *Session) SessionDiff {
+	var d SessionDiff
+	paired := make(map[*Movement]bool)
+
+	for _, am := range a.Movements {
+		var bm *Movement
+		for _, m := range b.Movements {
+			if !paired[m] && sameName(m.Name, am.Name) {
+				bm = m
+				break
+			}
+		}
+
+		if bm == nil {
+			d.Removed = append(d.Removed, am.Name)
+			continue
+		}
+		paired[bm] = true
+
+		if ps := diffPerformances(am.Performances, bm.Performances); len(ps) > 0 {
+			d.Changed = append(d.Changed, MovementDiff{Name: am.Name, Performances: ps})
+		}
+	}
+
+	for _, m := range b.Movements {
+		if !paired[m] {
+			d.Added = append(d.Added, m.Name)
+		}
+	}
+
+	return d
+}
+
+/* Private */
+
+func diffPerformances(before []*Performance, after []*Performance) []PerformanceDiff {
+	n := len(before)
+	if len(after) > n {
+		n = len(after)
+	}
+
+	var diffs []PerformanceDiff
+	for i := 0; i < n; i++ {
+		d := PerformanceDiff{Index: i}
+		if i < len(before) {
+			d.Before = before[i]
+		}
+		if i < len(after) {
+			d.After = after[i]
+		}
+
+		if d.Before != nil && d.After != nil &&
+			d.Before.Load == d.After.Load &&
+			d.Before.Reps == d.After.Reps &&
+			d.Before.Sets == d.After.Sets {
+			continue
+		}
+
+		diffs = append(diffs, d)
+	}
+	return diffs
+}
@@ -0,0 +1,75 @@
+package traindown
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDiffSessions(t *testing.T) {
+	a, err := ParseString(`
+    squat:
+      100 5r
+      110 5r
+
+    bench:
+      80 5r
+
+    row:
+      60 10r`)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	b, err := ParseString(`
+    Squat:
+      100 5r
+      115 5r
+      120 3r
+
+    row:
+      60 10r
+
+    deadlift:
+      140 5r`)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	d := DiffSessions(a, b)
+
+	if len(d.Added) != 1 || d.Added[0] != "deadlift" {
+		t.Errorf("Unexpected added movements: %v", d.Added)
+	}
+
+	if len(d.Removed) != 1 || d.Removed[0] != "bench" {
+		t.Errorf("Unexpected removed movements: %v", d.Removed)
+	}
+
+	if len(d.Changed) != 1 || d.Changed[0].Name != "squat" {
+		t.Fatalf("Unexpected changed movements: %v", d.Changed)
+	}
+
+	ps := d.Changed[0].Performances
+
+	if len(ps) != 2 {
+		t.Fatalf("Expected 2 changed performances, got %v", ps)
+	}
+
+	if ps[0].Index != 1 || ps[0].Before.Load != 110 || ps[0].After.Load != 115 {
+		t.Errorf("Unexpected change: %v", ps[0])
+	}
+
+	if ps[1].Index != 2 || ps[1].Before != nil || ps[1].After.Load != 120 {
+		t.Errorf("Unexpected addition: %v", ps[1])
+	}
+
+	if _, err := json.Marshal(d); err != nil {
+		t.Errorf("Failed to serialize diff: %q", err)
+	}
+
+	if d.Empty() || !DiffSessions(a, a).Empty() {
+		t.Error("Unexpected emptiness")
+	}
+}
@@ -0,0 +1,221 @@
+package traindown
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// LoadEpsilon is how far apart two loads, percentages, or RPEs may be and
+// still compare as equal.
+const LoadEpsilon = 0.001
+
+// Equal reports whether the sessions hold the same workout. See Mismatch.
+func (s *Session) Equal(other *Session) bool {
+	return s.Mismatch(other) == ""
+}
+
+// Mismatch describes the first difference found between the sessions, or
+// returns an empty string when they are equal. Dates, units, titles,
+// movements, performances, sets, metadata, and notes are compared by value,
+// so pointer identity and metadata order do not matter, and nil metadata or
+// notes equal empty ones. Loads compare within LoadEpsilon. Errors and source
+// spans are ignored, as in Fingerprint.
+func (s *Session) Mismatch(other *Session) string {
+	if !s.Date.Equal(other.Date) {
+		return fmt.Sprintf("date %v != %v", s.Date, other.Date)
+	}
+	if s.DefaultUnit != other.DefaultUnit {
+		return fmt.Sprintf("unit %q != %q", s.DefaultUnit, other.DefaultUnit)
+	}
+	if s.Title != other.Title {
+		return fmt.Sprintf("title %q != %q", s.Title, other.Title)
+	}
+	if d := metadataMismatch(s.Metadata, other.Metadata); d != "" {
+		return d
+	}
+	if d := notesMismatch(s.Notes, other.Notes); d != "" {
+		return d
+	}
+	if len(s.Movements) != len(other.Movements) {
+		return fmt.Sprintf("%d movements != %d", len(s.Movements), len(other.Movements))
+	}
+
+	for i, m := range s.Movements {
+		if d := m.mismatch(other.Movements[i]); d != "" {
+			return fmt.Sprintf("movement #%d: %s", i+1, d)
+		}
+	}
+
+	return ""
+}
+
+/* Private */
+
+func (m *Movement) mismatch(other *Movement) string {
+	switch {
+	case m.Name != other.Name:
+		return fmt.Sprintf("name %q != %q", m.Name, other.Name)
+	case m.DefaultUnit != other.DefaultUnit:
+		return fmt.Sprintf("unit %q != %q", m.DefaultUnit, other.DefaultUnit)
+	case m.Sequence != other.Sequence:
+		return fmt.Sprintf("sequence %d != %d", m.Sequence, other.Sequence)
+	case m.SuperSet != other.SuperSet:
+		return fmt.Sprintf("superset %v != %v", m.SuperSet, other.SuperSet)
+	}
+	if d := metadataMismatch(m.Metadata, other.Metadata); d != "" {
+		return d
+	}
+	if d := notesMismatch(m.Notes, other.Notes); d != "" {
+		return d
+	}
+	if len(m.Performances) != len(other.Performances) {
+		return fmt.Sprintf("%d performances != %d", len(m.Performances), len(other.Performances))
+	}
+
+	for i, p := range m.Performances {
+		if d := p.mismatch(other.Performances[i]); d != "" {
+			return fmt.Sprintf("performance #%d: %s", i+1, d)
+		}
+	}
+
+	return ""
+}
+
+func (p *Performance) mismatch(other *Performance) string {
+	switch {
+	case !floatEqual(p.Load, other.Load):
+		return fmt.Sprintf("load %v != %v", p.Load, other.Load)
+	case !rangeEqual(p, other):
+		return fmt.Sprintf("load range %v-%v != %v-%v", p.LoadMin, p.LoadMax, other.LoadMin, other.LoadMax)
+	case !componentsEqual(p.LoadComponents, other.LoadComponents):
+		return fmt.Sprintf("load components %v != %v", p.LoadComponents, other.LoadComponents)
+	case !modifiersEqual(p.LoadModifiers, other.LoadModifiers):
+		return fmt.Sprintf("load modifiers %v != %v", p.LoadModifiers, other.LoadModifiers)
+	case p.Unit != other.Unit:
+		return fmt.Sprintf("unit %q != %q", p.Unit, other.Unit)
+	case p.RelativeLoad != other.RelativeLoad:
+		return fmt.Sprintf("relative load %q != %q", p.RelativeLoad, other.RelativeLoad)
+	case !floatEqual(p.PercentOfMax, other.PercentOfMax) || p.PrescribedPercent != other.PrescribedPercent:
+		return fmt.Sprintf("percent of max %v != %v", p.PercentOfMax, other.PercentOfMax)
+	case p.Reps != other.Reps:
+		return fmt.Sprintf("reps %d != %d", p.Reps, other.Reps)
+	case !reflect.DeepEqual(p.RepRange, other.RepRange):
+		return fmt.Sprintf("rep range %v != %v", p.RepRange, other.RepRange)
+	case !intsEqual(p.PerSetReps, other.PerSetReps):
+		return fmt.Sprintf("per set reps %v != %v", p.PerSetReps, other.PerSetReps)
+	case p.Sets != other.Sets:
+		return fmt.Sprintf("sets %d != %d", p.Sets, other.Sets)
+	case p.Fails != other.Fails:
+		return fmt.Sprintf("fails %d != %d", p.Fails, other.Fails)
+	case p.Rest != other.Rest:
+		return fmt.Sprintf("rest %v != %v", p.Rest, other.Rest)
+	case !floatEqual(p.RPE, other.RPE):
+		return fmt.Sprintf("rpe %v != %v", p.RPE, other.RPE)
+	case !reflect.DeepEqual(p.Tempo, other.Tempo):
+		return fmt.Sprintf("tempo %v != %v", p.Tempo, other.Tempo)
+	case p.Sequence != other.Sequence:
+		return fmt.Sprintf("sequence %d != %d", p.Sequence, other.Sequence)
+	}
+	if d := metadataMismatch(p.Metadata, other.Metadata); d != "" {
+		return d
+	}
+	if d := notesMismatch(p.Notes, other.Notes); d != "" {
+		return d
+	}
+	if len(p.SetDetails) != len(other.SetDetails) {
+		return fmt.Sprintf("%d set details != %d", len(p.SetDetails), len(other.SetDetails))
+	}
+
+	for i, s := range p.SetDetails {
+		if d := s.mismatch(other.SetDetails[i]); d != "" {
+			return fmt.Sprintf("set #%d: %s", i+1, d)
+		}
+	}
+
+	return ""
+}
+
+func (s *Set) mismatch(other *Set) string {
+	switch {
+	case s.Reps != other.Reps:
+		return fmt.Sprintf("reps %d != %d", s.Reps, other.Reps)
+	case s.Fails != other.Fails:
+		return fmt.Sprintf("fails %d != %d", s.Fails, other.Fails)
+	}
+	if d := metadataMismatch(s.Metadata, other.Metadata); d != "" {
+		return d
+	}
+	return notesMismatch(s.Notes, other.Notes)
+}
+
+func floatEqual(a float32, b float32) bool {
+	return math.Abs(float64(a-b)) <= LoadEpsilon
+}
+
+func rangeEqual(a *Performance, b *Performance) bool {
+	amin, amax := a.bounds()
+	bmin, bmax := b.bounds()
+	return floatEqual(amin, bmin) && floatEqual(amax, bmax)
+}
+
+func intsEqual(a []int, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func componentsEqual(a []LoadComponent, b []LoadComponent) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Unit != b[i].Unit || a[i].Label != b[i].Label || !floatEqual(a[i].Load, b[i].Load) {
+			return false
+		}
+	}
+	return true
+}
+
+func modifiersEqual(a []LoadModifier, b []LoadModifier) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Type != b[i].Type || !floatEqual(a[i].Magnitude, b[i].Magnitude) {
+			return false
+		}
+	}
+	return true
+}
+
+func metadataMismatch(a Metadata, b Metadata) string {
+	if len(a) != len(b) {
+		return fmt.Sprintf("%d metadata != %d", len(a), len(b))
+	}
+	for k, v := range a {
+		ov, ok := b[k]
+		if !ok || !reflect.DeepEqual(v, ov) {
+			return fmt.Sprintf("metadata %q: %v != %v", k, v, ov)
+		}
+	}
+	return ""
+}
+
+func notesMismatch(a []string, b []string) string {
+	if len(a) != len(b) {
+		return fmt.Sprintf("%d notes != %d", len(a), len(b))
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return fmt.Sprintf("note %q != %q", a[i], b[i])
+		}
+	}
+	return ""
+}
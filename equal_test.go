@@ -0,0 +1,84 @@
+package traindown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEqual(t *testing.T) {
+	a, err := ParseString(`
+    @ 2020-01-01
+    # a: 1
+    # b: 2
+
+    squat:
+      100 5r
+        * easy`)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	b, err := ParseString(`
+    @ 2020-01-01
+    # b: 2
+    # a: 1
+
+    squat:
+      100.0001 5r
+        * easy`)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if !a.Equal(b) || !b.Equal(a) {
+		t.Errorf("Expected equal sessions: %s", a.Mismatch(b))
+	}
+
+	if !a.Equal(a.Clone()) {
+		t.Error("Expected a clone to be equal")
+	}
+
+	b.Movements[0].Performances[0].Load = 105
+
+	if a.Equal(b) || !strings.Contains(a.Mismatch(b), "load 100 != 105") {
+		t.Errorf("Unexpected mismatch: %q", a.Mismatch(b))
+	}
+
+	b = a.Clone()
+	b.Metadata["a"] = "3"
+
+	if d := a.Mismatch(b); !strings.Contains(d, `metadata "a"`) {
+		t.Errorf("Unexpected mismatch: %q", d)
+	}
+}
+
+func TestEqualResolvedLoadRange(t *testing.T) {
+	parsed, err := ParseString("@ 2020-01-01\n# bw: 80\n\nsquat:\n  100 5r\n\ndip:\n  bw 5r\n")
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if p := parsed.Movements[1].Performances[0]; p.Load != 80 || p.LoadMin != 80 || p.LoadMax != 80 {
+		t.Errorf("Failed to set the range of a bodyweight load: %v", p)
+	}
+
+	built := &Session{Date: parsed.Date, Metadata: Metadata{"bw": "80"}}
+	for i, l := range []float32{100, 80} {
+		m := NewMovement()
+		m.Name = parsed.Movements[i].Name
+		m.Sequence = i + 1
+
+		p := NewPerformance()
+		p.Load, p.Reps, p.Sequence = l, 5, 1
+		p.RelativeLoad = parsed.Movements[i].Performances[0].RelativeLoad
+		m.Performances = append(m.Performances, p)
+		built.Movements = append(built.Movements, m)
+	}
+
+	if d := built.Mismatch(parsed); d != "" {
+		t.Errorf("Expected a hand built session to equal the parsed one: %s", d)
+	}
+}
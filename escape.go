@@ -0,0 +1,80 @@
+package traindown
+
+import (
+	"strings"
+)
+
+// EscapeChar escapes characters in notes and metadata that would otherwise
+// be read as grammar. "\n" and "\r" stand for line breaks, and any other
+// escaped character, such as "\," or "\:" in metadata, stands for itself.
+const EscapeChar = '\\'
+
+// Escape sequences written by Marshal.
+var (
+	noteEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\r", `\r`)
+	keyEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\r", `\r`, ":", `\:`, ",", `\,`)
+	valEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\r", `\r`, ",", `\,`)
+)
+
+/* Private */
+
+func escapeNote(s string) string {
+	return noteEscaper.Replace(s)
+}
+
+func escapeKey(s string) string {
+	return keyEscaper.Replace(s)
+}
+
+func escapeValue(s string) string {
+	return valEscaper.Replace(s)
+}
+
+// unescape reverses the escaping applied by Marshal.
+func unescape(s string) string {
+	if strings.IndexByte(s, EscapeChar) < 0 {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != EscapeChar || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// indexUnescaped is strings.IndexAny skipping over escaped characters.
+func indexUnescaped(s string, chars string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == EscapeChar {
+			i++
+			continue
+		}
+		if strings.IndexByte(chars, s[i]) >= 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// splitKeyValue splits a metadata pair at its first unescaped colon.
+func splitKeyValue(kv string) (string, string, bool) {
+	i := indexUnescaped(kv, ":")
+	if i < 0 {
+		return kv, "", false
+	}
+	return kv[:i], kv[i+1:], true
+}
@@ -0,0 +1,50 @@
+package traindown
+
+import (
+	"testing"
+)
+
+func TestUnescape(t *testing.T) {
+	cases := map[string]string{
+		`plain`:         "plain",
+		`a\nb`:          "a\nb",
+		`a\\nb`:         `a\nb`,
+		`a\,b\:c`:       "a,b:c",
+		`trailing\`:     `trailing\`,
+		`\r\n`:          "\r\n",
+		`* # @ :`:       "* # @ :",
+		`escaped \* ok`: "escaped * ok",
+	}
+
+	for in, want := range cases {
+		if got := unescape(in); got != want {
+			t.Errorf("Incorrect unescape of %q: %q", in, got)
+		}
+	}
+}
+
+func TestEscapeRoundTrip(t *testing.T) {
+	for _, s := range []string{"plain", "a\nb", `a\b`, "a, b: c", "* # @ :", `\n`} {
+		if got := unescape(escapeNote(s)); got != s {
+			t.Errorf("Note %q came back as %q", s, got)
+		}
+		if got := unescape(escapeKey(s)); got != s {
+			t.Errorf("Key %q came back as %q", s, got)
+		}
+		if got := unescape(escapeValue(s)); got != s {
+			t.Errorf("Value %q came back as %q", s, got)
+		}
+	}
+}
+
+func TestSplitKeyValue(t *testing.T) {
+	k, v, ok := splitKeyValue(`a\:b: c:d`)
+
+	if !ok || k != `a\:b` || v != " c:d" {
+		t.Errorf("Incorrect split: %q %q", k, v)
+	}
+
+	if _, _, ok = splitKeyValue(`no\: colon`); ok {
+		t.Errorf("Split on an escaped colon")
+	}
+}
@@ -0,0 +1,65 @@
+package traindown
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// Fingerprint returns a hex encoded SHA-256 of the Session content. The hash
+// covers the JSON form of the Session, which orders metadata keys, so it does
+// not depend on map iteration. Errors and source spans are left out, so the
+// same workout hashes alike wherever it sits in a file. Non-finite metadata
+// numbers are hashed as text; an error is returned when the Session still
+// cannot be encoded, such as with an infinite Load.
+func (s *Session) Fingerprint() (string, error) {
+	c := s.Clone()
+	c.Errors = nil
+
+	c.Walk(func(node interface{}) bool {
+		switch n := node.(type) {
+		case *Session:
+			finiteMetadata(n.Metadata)
+		case *Movement:
+			n.Span = nil
+			finiteMetadata(n.Metadata)
+		case *Performance:
+			n.Span = nil
+			finiteMetadata(n.Metadata)
+		case *Set:
+			finiteMetadata(n.Metadata)
+		}
+		return true
+	})
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("Failed to fingerprint session: %q", err)
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+/* Private */
+
+// finiteMetadata replaces the infinite and NaN numbers of md, which JSON
+// cannot hold, with their text.
+func finiteMetadata(md Metadata) {
+	for k, v := range md {
+		var f float64
+		switch n := v.(type) {
+		case float32:
+			f = float64(n)
+		case float64:
+			f = n
+		default:
+			continue
+		}
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			md[k] = fmt.Sprint(v)
+		}
+	}
+}
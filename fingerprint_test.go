@@ -0,0 +1,73 @@
+package traindown
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	a, err := ParseString("@ 2020-01-01\n# a: 1\n# b: 2\n# c: 3\nsquat:\n  100 5r")
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	b, err := ParseStringWithOptions("@ 2020-01-01\n# c: 3\n# b: 2\n# a: 1\n\n\nsquat:\n  100 5r // same", WithSourceSpans(true))
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	fp, err := a.Fingerprint()
+
+	if err != nil || len(fp) != 64 {
+		t.Errorf("Expected a hex SHA-256, got %q", fp)
+	}
+
+	for i := 0; i < 10; i++ {
+		if got, _ := b.Fingerprint(); got != fp {
+			t.Fatalf("Fingerprint depends on order: %q %q", got, fp)
+		}
+	}
+
+	if b.Movements[0].Span == nil {
+		t.Error("Fingerprint modified the session")
+	}
+
+	b.Movements[0].Performances[0].Reps = 6
+
+	if got, _ := b.Fingerprint(); got == fp {
+		t.Error("Expected a different fingerprint after a change")
+	}
+}
+
+func TestFingerprintNonFinite(t *testing.T) {
+	a, _ := ParseString("@ 2020-01-01\nsquat:\n  100 5r")
+	b := a.Clone()
+	a.Metadata["x"] = math.Inf(1)
+	b.Metadata["x"] = math.NaN()
+
+	fa, err := a.Fingerprint()
+	if err != nil {
+		t.Fatalf("Failed to fingerprint: %q", err)
+	}
+
+	fb, err := b.Fingerprint()
+	if err != nil {
+		t.Fatalf("Failed to fingerprint: %q", err)
+	}
+
+	if fa == fb {
+		t.Errorf("Expected different fingerprints, got %q", fa)
+	}
+
+	if _, ok := a.Metadata["x"].(float64); !ok {
+		t.Error("Fingerprint modified the session")
+	}
+
+	a.Movements[0].Performances[0].Load = float32(math.Inf(1))
+
+	if _, err := a.Fingerprint(); err == nil {
+		t.Error("Expected an error for an infinite load")
+	}
+}
@@ -48,6 +48,17 @@ func (f Formatter) Format(txt string) (string, error) {
 
 	for _, tok := range tokens {
 		switch tok.Name() {
+		case "ALIAS":
+			alias, name := splitAlias(tok.Value())
+			s.WriteString("alias ")
+			s.WriteString(alias)
+			s.WriteString(" = ")
+			s.WriteString(name)
+			s.WriteString("\r\n")
+		case "COMMENT":
+			s.WriteString(" // ")
+			s.WriteString(tok.Value())
+			s.WriteString("\r\n")
 		case "DATE":
 			s.WriteString("@ ")
 			s.WriteString(tok.Value())
@@ -61,6 +72,9 @@ func (f Formatter) Format(txt string) (string, error) {
 			s.WriteString("\r\n")
 			s.WriteString("  ")
 			s.WriteString(tok.Value())
+		case "MODIFIER":
+			s.WriteString(" ")
+			s.WriteString(tok.Value())
 		case "METADATA":
 			s.WriteString("\r\n")
 			s.WriteString(spacer(inSession, inPerformance))
@@ -84,6 +98,17 @@ func (f Formatter) Format(txt string) (string, error) {
 			s.WriteString(" ")
 			s.WriteString(tok.Value())
 			s.WriteString("r")
+		case "SCHEME":
+			s.WriteString(" ")
+			s.WriteString(tok.Value())
+		case "UNIT":
+			s.WriteString("\r\n")
+			s.WriteString(spacer(inSession, inPerformance))
+			s.WriteString("unit ")
+			s.WriteString(tok.Value())
+		case "SET":
+			s.WriteString("\r\n")
+			s.WriteString("    -")
 		case "SETS":
 			s.WriteString(" ")
 			s.WriteString(tok.Value())
@@ -3,6 +3,7 @@ package traindown
 import (
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestFormat(t *testing.T) {
@@ -40,3 +41,54 @@ func TestFormat(t *testing.T) {
 		t.Errorf("Output mismatch:\n\nGot:\n%q\n\nExpected:\n%q", res, expected)
 	}
 }
+
+func TestFormatComments(t *testing.T) {
+	fmtr, err := NewFormatter()
+
+	if err != nil {
+		t.Fatalf("Failed to init formatter")
+	}
+
+	res, err := fmtr.Format("movement:\n100 2r   //    heavy")
+
+	if err != nil {
+		t.Fatalf("Failed formatting: %q", err)
+	}
+
+	expected := "\r\n\r\nmovement:\r\n  100 2r // heavy\r\n"
+
+	if res != expected {
+		t.Errorf("Output mismatch:\n\nGot:\n%q\n\nExpected:\n%q", res, expected)
+	}
+}
+
+func TestFormatCommentsBeforeLines(t *testing.T) {
+	fmtr, err := NewFormatter()
+
+	if err != nil {
+		t.Fatalf("Failed to init formatter")
+	}
+
+	res, err := fmtr.Format("// first\n@ 2020-01-02\n// second\nalias bp = Bench Press\nbp:\n  100 5r")
+
+	if err != nil {
+		t.Fatalf("Failed formatting: %q", err)
+	}
+
+	expected := " // first\r\n@ 2020-01-02\r\n // second\r\nalias bp = Bench Press\r\n\r\n\r\nbp:\r\n  100 5r"
+
+	if res != expected {
+		t.Errorf("Output mismatch:\n\nGot:\n%q\n\nExpected:\n%q", res, expected)
+	}
+
+	session, err := ParseString(res)
+
+	if err != nil {
+		t.Fatalf("Failed to parse the formatted text: %q", err)
+	}
+
+	if session.Date != time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC) ||
+		len(session.Movements) != 1 || session.Movements[0].Name != "Bench Press" {
+		t.Errorf("Formatting lost the date or alias: %v", session)
+	}
+}
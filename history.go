@@ -0,0 +1,166 @@
+package traindown
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// MovementPoint summarizes one day of a Movement.
+type MovementPoint struct {
+	Date               time.Time    `json:"date"`
+	TopSet             *Performance `json:"topSet"`
+	EstimatedOneRepMax float32      `json:"estimatedOneRepMax"`
+	Volume             float32      `json:"volume"`
+}
+
+// AggregateMovement builds the timeline of the named Movement across
+// sessions, oldest first. Names match as in MovementsByName, and sessions
+// without the Movement are skipped. When a Movement appears more than once in
+// a Session the appearances are combined. The estimate uses Epley.
+func AggregateMovement(sessions []*Session, name string) []MovementPoint {
+	var points []MovementPoint
+
+	for _, s := range sessions {
+		ms := s.MovementsByName(name)
+		if len(ms) == 0 {
+			continue
+		}
+
+		pt := MovementPoint{Date: s.Date}
+		for _, m := range ms {
+			if top := m.TopSet(); top != nil && (pt.TopSet == nil || top.Load > pt.TopSet.Load) {
+				pt.TopSet = top
+			}
+
+			if e := m.BestEstimatedOneRepMax(Epley); e > pt.EstimatedOneRepMax {
+				pt.EstimatedOneRepMax = e
+			}
+
+			v, _ := m.Volume()
+			pt.Volume += v
+		}
+
+		points = append(points, pt)
+	}
+
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].Date.Before(points[j].Date)
+	})
+
+	return points
+}
+
+// OneRepMaxTrend is the slope, in load per day, of a least squares line
+// through the best estimated one rep max of the named Movement in each
+// Session, by formula. Names match as in MovementsByName, and sessions
+// without the Movement or an estimate are skipped. An error is returned when
+// fewer than two sessions remain or they all fall on the same day.
+func OneRepMaxTrend(sessions []*Session, movement string, formula string) (float32, error) {
+	var xs, ys []float64
+
+	for _, s := range sessions {
+		var best float32
+		for _, m := range s.MovementsByName(movement) {
+			if e := m.BestEstimatedOneRepMax(formula); e > best {
+				best = e
+			}
+		}
+
+		if best == 0 {
+			continue
+		}
+
+		xs = append(xs, float64(s.Date.Unix())/(24*60*60))
+		ys = append(ys, float64(best))
+	}
+
+	if len(xs) < 2 {
+		return 0, fmt.Errorf("Fewer than two estimates for %q", movement)
+	}
+
+	n := float64(len(xs))
+	var sx, sy float64
+	for i := range xs {
+		sx += xs[i]
+		sy += ys[i]
+	}
+	mx, my := sx/n, sy/n
+
+	var sxy, sxx float64
+	for i := range xs {
+		sxy += (xs[i] - mx) * (ys[i] - my)
+		sxx += (xs[i] - mx) * (xs[i] - mx)
+	}
+
+	if sxx == 0 {
+		return 0, fmt.Errorf("Estimates for %q all fall on one day", movement)
+	}
+
+	return float32(sxy / sxx), nil
+}
+
+// PR is the personal record of a Movement.
+type PR struct {
+	Name               string       `json:"name"`
+	Date               time.Time    `json:"date"`
+	Load               float32      `json:"load"`
+	Unit               string       `json:"unit"`
+	EstimatedOneRepMax float32      `json:"estimatedOneRepMax"`
+	Performance        *Performance `json:"performance"`
+}
+
+// PersonalRecords finds the heaviest Load of each Movement across sessions,
+// keyed by the lowercased and trimmed movement name; run CanonicalizeNames
+// first to fold aliases together. Ties break to the earliest date. Units are
+// not converted and the estimate uses Epley.
+func PersonalRecords(sessions []*Session) map[string]PR {
+	return personalRecords(sessions, func(p *Performance) float32 {
+		return p.Load
+	})
+}
+
+// PersonalRecordsByOneRepMax is PersonalRecords ranked by the Epley
+// EstimatedOneRepMax instead of the Load.
+func PersonalRecordsByOneRepMax(sessions []*Session) map[string]PR {
+	return personalRecords(sessions, func(p *Performance) float32 {
+		return p.EstimatedOneRepMax(Epley)
+	})
+}
+
+/* Private */
+
+func personalRecords(sessions []*Session, score func(*Performance) float32) map[string]PR {
+	prs := make(map[string]PR)
+	scores := make(map[string]float32)
+
+	for _, s := range sessions {
+		for _, m := range s.Movements {
+			k := nameKey(m.Name)
+
+			for _, p := range m.Performances {
+				v := score(p)
+				if v <= 0 {
+					continue
+				}
+
+				pr, ok := prs[k]
+				if ok && (v < scores[k] || (v == scores[k] && !s.Date.Before(pr.Date))) {
+					continue
+				}
+
+				scores[k] = v
+				prs[k] = PR{
+					Name:               m.Name,
+					Date:               s.Date,
+					Load:               p.Load,
+					Unit:               p.Unit,
+					EstimatedOneRepMax: p.EstimatedOneRepMax(Epley),
+					Performance:        p,
+				}
+			}
+		}
+	}
+
+	return prs
+}
@@ -0,0 +1,136 @@
+package traindown
+
+import (
+	"testing"
+	"time"
+)
+
+func parseSessionsCheck(t *testing.T, text string) []*Session {
+	sessions, err := ParseSessions(text)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	return sessions
+}
+
+func TestAggregateMovement(t *testing.T) {
+	sessions := parseSessionsCheck(t, `
+    @ 2020-01-08
+    Squat:
+      300 1r
+    bench:
+      200 5r
+    squat:
+      315 1r
+
+    @ 2020-01-01
+    squat:
+      200 10r
+      250 1r
+
+    @ 2020-01-04
+    bench:
+      200 5r`)
+
+	points := AggregateMovement(sessions, "squat")
+
+	if len(points) != 2 {
+		t.Fatalf("Expected 2 points, got %d", len(points))
+	}
+
+	p0 := points[0]
+	if p0.Date != time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC) ||
+		p0.TopSet.Load != 250 ||
+		p0.EstimatedOneRepMax != 200*(1+float32(10)/30) ||
+		p0.Volume != 2250 {
+		t.Errorf("Incorrect first point: %+v", p0)
+	}
+
+	p1 := points[1]
+	if p1.Date != time.Date(2020, 1, 8, 0, 0, 0, 0, time.UTC) ||
+		p1.TopSet.Load != 315 ||
+		p1.EstimatedOneRepMax != 315 ||
+		p1.Volume != 615 {
+		t.Errorf("Incorrect second point: %+v", p1)
+	}
+
+	if points = AggregateMovement(sessions, "deadlift"); len(points) != 0 {
+		t.Errorf("Expected no points, got %v", points)
+	}
+}
+
+func TestPersonalRecords(t *testing.T) {
+	sessions := parseSessionsCheck(t, `
+    @ 2020-01-08
+    Squat:
+      300 3r
+    bench:
+      200 5r
+
+    @ 2020-01-01
+    squat:
+      200 10r
+      300 1r
+
+    @ 2020-01-04
+    bench:
+      200 5r
+    row:
+      bw 10r`)
+
+	prs := PersonalRecords(sessions)
+
+	if len(prs) != 2 {
+		t.Fatalf("Expected 2 records, got %v", prs)
+	}
+
+	squat := prs["squat"]
+	if squat.Load != 300 || squat.Date != time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC) || squat.EstimatedOneRepMax != 300 {
+		t.Errorf("Expected the earliest heaviest squat, got %+v", squat)
+	}
+
+	if bench := prs["bench"]; bench.Date != time.Date(2020, 1, 4, 0, 0, 0, 0, time.UTC) {
+		t.Errorf("Expected the earliest heaviest bench, got %+v", bench)
+	}
+
+	prs = PersonalRecordsByOneRepMax(sessions)
+
+	if squat := prs["squat"]; squat.Date != time.Date(2020, 1, 8, 0, 0, 0, 0, time.UTC) || squat.Name != "Squat" || squat.Performance.Reps != 3 {
+		t.Errorf("Expected the best estimated squat, got %+v", squat)
+	}
+}
+
+func TestOneRepMaxTrend(t *testing.T) {
+	sessions := parseSessionsCheck(t, `
+    @ 2020-01-11
+    squat:
+      220 1r
+
+    @ 2020-01-01
+    squat:
+      200 1r
+
+    @ 2020-01-05
+    bench:
+      100 1r
+
+    @ 2020-01-06
+    squat:
+      210 1r`)
+
+	slope, err := OneRepMaxTrend(sessions, "Squat", Epley)
+
+	if err != nil || slope != 2 {
+		t.Errorf("Expected a slope of 2 per day, got %v (%v)", slope, err)
+	}
+
+	if _, err := OneRepMaxTrend(sessions, "bench", Epley); err == nil {
+		t.Error("Expected an error for a single estimate")
+	}
+
+	if _, err := OneRepMaxTrend(append(sessions[3:], sessions[3]), "squat", Epley); err == nil {
+		t.Error("Expected an error for estimates on one day")
+	}
+}
@@ -0,0 +1,19 @@
+//go:build go1.23
+
+package traindown
+
+import "iter"
+
+// Performances iterates over every Performance in the Session, in order,
+// along with the Movement it belongs to.
+func (s *Session) Performances() iter.Seq2[*Movement, *Performance] {
+	return func(yield func(*Movement, *Performance) bool) {
+		for _, m := range s.Movements {
+			for _, p := range m.Performances {
+				if !yield(m, p) {
+					return
+				}
+			}
+		}
+	}
+}
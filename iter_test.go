@@ -0,0 +1,30 @@
+//go:build go1.23
+
+package traindown
+
+import (
+	"testing"
+)
+
+func TestPerformances(t *testing.T) {
+	session, err := ParseString("squat:\n  100\n  200\n\nbench:\n  300")
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	var loads []float32
+	for m, p := range session.Performances() {
+		if m.Performances[p.Sequence-1] != p {
+			t.Errorf("Performance %v not owned by %q", p, m.Name)
+		}
+		loads = append(loads, p.Load)
+		if p.Load == 200 {
+			break
+		}
+	}
+
+	if len(loads) != 2 || loads[0] != 100 || loads[1] != 200 {
+		t.Errorf("Unexpected loads before break: %v", loads)
+	}
+}
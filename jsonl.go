@@ -0,0 +1,52 @@
+package traindown
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// WriteJSONL writes each Session to w as compact JSON on a line of its own,
+// ready for tools such as jq that read newline delimited JSON.
+func WriteJSONL(w io.Writer, sessions []*Session) error {
+	enc := json.NewEncoder(w)
+
+	for _, s := range sessions {
+		if err := enc.Encode(s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReadJSONL reads the sessions written by WriteJSONL, skipping blank lines.
+// Errors do not survive JSON, so they are not read back, and metadata values
+// come back as strings, bools, and float64s.
+func ReadJSONL(r io.Reader) ([]*Session, error) {
+	dec := json.NewDecoder(r)
+	sessions := []*Session{}
+
+	for {
+		js := jsonlSession{Session: &Session{}}
+		err := dec.Decode(&js)
+
+		if err == io.EOF {
+			return sessions, nil
+		}
+
+		if err != nil {
+			return sessions, fmt.Errorf("Failed to read session %d: %q", len(sessions)+1, err)
+		}
+
+		sessions = append(sessions, js.Session)
+	}
+}
+
+/* Private */
+
+// jsonlSession shadows the Errors of a Session, which cannot be decoded.
+type jsonlSession struct {
+	*Session
+	Errors json.RawMessage `json:"errors,omitempty"`
+}
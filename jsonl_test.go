@@ -0,0 +1,67 @@
+package traindown
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWriteJSONL(t *testing.T) {
+	sessions, err := ParseSessions(`
+    @ 2020-01-01
+    # bw: 180
+    squat:
+      225 5r 3s
+        * felt good
+
+    @ 2020-01-02
+    bench:
+      135 5.5r`)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	var b bytes.Buffer
+
+	if err := WriteJSONL(&b, sessions); err != nil {
+		t.Fatalf("Failed to write: %q", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+
+	if len(lines) != 2 {
+		t.Fatalf("Expected a line per session, got %q", b.String())
+	}
+
+	for _, l := range lines {
+		if !json.Valid([]byte(l)) {
+			t.Errorf("Invalid JSON line: %q", l)
+		}
+	}
+
+	read, err := ReadJSONL(strings.NewReader(b.String() + "\n\n"))
+
+	if err != nil {
+		t.Fatalf("Failed to read: %q", err)
+	}
+
+	if len(read) != 2 || read[1].Errors != nil {
+		t.Fatalf("Unexpected sessions: %v", read)
+	}
+
+	for i, s := range read {
+		if d := sessions[i].Mismatch(s); d != "" {
+			t.Errorf("Session %d mismatch: %s", i, d)
+		}
+	}
+
+	if _, err := ReadJSONL(strings.NewReader(lines[0] + "\n{")); err == nil {
+		t.Error("Expected an error for a truncated line")
+	}
+
+	if err := WriteJSONL(failingWriter{}, sessions); err == nil || err.Error() != "your mom" {
+		t.Errorf("Expected the writer error, got %v", err)
+	}
+}
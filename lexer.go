@@ -2,15 +2,44 @@ package traindown
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/timtadh/lexmachine"
 	"github.com/timtadh/lexmachine/machines"
 )
 
-// Tokens used in parsing Traindown inputs
+// Tokens used in parsing Traindown inputs:
+//
+//	DATE         "@ 2020-01-01", the date of a session
+//	LOAD         "100", "100kg", "80%", a range "225-245", relative to
+//	             bodyweight "BW+20kg", or "same" as the last when it leads its
+//	             line, the start of a performance
+//	FAILS        "1f", failed reps
+//	METADATA     "# key: value", normalized to "key: value" and left escaped
+//	MOVEMENT     "Squat:"
+//	MOVEMENT_SS  "+ Squat:", a movement supersetted with the previous one
+//	NOTE         "* note"
+//	REPS         "5r", a range "8-12r", or reps for each set "5,5,3r"
+//	SETS         "3s"
+//	COMMENT      "// comment", ignored by the parser
+//	SCHEME       "5x3", sets by reps
+//	UNIT         "unit kg", the unit for the enclosing scope like "# unit: kg"
+//	SET          "-", a set of the performance before it, only as the first
+//	             thing on its line
+//	MODIFIER     "+40band" or "-50chain", accommodating resistance, or
+//	             "+ 20kg collars", another part of the load
+//	ALIAS        "alias bp = Bench Press", normalized to "bp=Bench Press"
+//
+// The markers for fails, reps, and sets are case insensitive and may also
+// lead the number, as in "R 5". Their numbers may carry a decimal, as in
+// "5.0r", which the parser rejects unless integral.
 var Tokens = []string{
 	"DATE", "LOAD", "FAILS", "METADATA", "MOVEMENT", "MOVEMENT_SS", "NOTE", "REPS", "SETS",
+	"COMMENT", "SCHEME", "UNIT", "SET", "MODIFIER", "ALIAS",
 }
 
 // Token holds information about a token
@@ -28,8 +57,11 @@ func init() {
 	}
 }
 
-// Name getter
+// Name getter, empty for a type outside of Tokens
 func (tok Token) Name() string {
+	if tok.t.Type < 0 || tok.t.Type >= len(Tokens) {
+		return ""
+	}
 	return Tokens[tok.t.Type]
 }
 
@@ -53,12 +85,26 @@ func (tok Token) End() (int, int) {
 	return tok.t.EndLine, tok.t.EndColumn
 }
 
+// Span getter returns the byte offsets of the token in the source, with the
+// end exclusive.
+func (tok Token) Span() (int, int) {
+	return tok.t.TC, tok.t.TC + len(tok.t.Lexeme)
+}
+
 // String override
 func (tok *Token) String() string {
 	return fmt.Sprintf("%q %q (From: r%d, c%d To: r%d c%d)", tok.Name(), tok.t.Value, tok.t.StartLine, tok.t.StartColumn, tok.t.EndLine, tok.t.EndColumn)
 }
 
-// Lexer type
+// Tokenize scans txt and returns its tokens.
+func Tokenize(txt string) ([]*Token, error) {
+	return scan([]byte(txt))
+}
+
+// Lexer type. Compiling a Lexer in NewLexer is costly, so one Lexer should be
+// kept and reused across inputs rather than made per Scan. A Lexer must only
+// be used by one goroutine at a time; the parse functions share a pool of
+// them.
 type Lexer struct {
 	l *lexmachine.Lexer
 }
@@ -68,7 +114,7 @@ func NewLexer() (Lexer, error) {
 	var lexer = lexmachine.NewLexer()
 
 	lexer.Add(
-		[]byte("@[^\n]*"),
+		[]byte(`@[^\n\r]*`),
 		func(scan *lexmachine.Scanner, match *machines.Match) (interface{}, error) {
 			return scan.Token(
 					TokenMap["DATE"],
@@ -78,15 +124,15 @@ func NewLexer() (Lexer, error) {
 		},
 	)
 	lexer.Add(
-		[]byte(`#[^\n|\r][a-zA-Z| |:|[0-9]+`),
+		[]byte(`#[^\n\r]*`),
 		func(scan *lexmachine.Scanner, match *machines.Match) (interface{}, error) {
-			lR := strings.Split(string(match.Bytes)[1:], ":")
-			l := strings.TrimSpace(lR[0])
-			r := strings.TrimSpace(lR[1])
+			l, r, ok := splitKeyValue(string(match.Bytes)[1:])
 			var kvp strings.Builder
-			kvp.WriteString(l)
-			kvp.WriteString(": ")
-			kvp.WriteString(r)
+			kvp.WriteString(strings.TrimSpace(l))
+			if ok {
+				kvp.WriteString(": ")
+				kvp.WriteString(strings.TrimSpace(r))
+			}
 			return scan.Token(
 					TokenMap["METADATA"],
 					kvp.String(),
@@ -95,56 +141,120 @@ func NewLexer() (Lexer, error) {
 		},
 	)
 	lexer.Add(
-		[]byte(`\*[^\n|\r]*`),
+		[]byte(`//[^\n\r]*`),
+		func(scan *lexmachine.Scanner, match *machines.Match) (interface{}, error) {
+			return scan.Token(
+					TokenMap["COMMENT"],
+					strings.TrimSpace(string(match.Bytes)[2:]),
+					match),
+				nil
+		},
+	)
+	lexer.Add(
+		[]byte(`\*[^\n\r]*`),
 		func(scan *lexmachine.Scanner, match *machines.Match) (interface{}, error) {
 			return scan.Token(
 					TokenMap["NOTE"],
-					strings.TrimSpace(string(match.Bytes)[1:]),
+					unescape(strings.TrimSpace(string(match.Bytes)[1:])),
 					match),
 				nil
 		},
 	)
 	lexer.Add(
-		[]byte(`[0-9]+[fF]`),
+		[]byte(`[0-9]+(\.[0-9]+)?[fF]|[fF][ \t]*[0-9]+(\.[0-9]+)?`),
 		func(scan *lexmachine.Scanner, match *machines.Match) (interface{}, error) {
-			s := string(match.Bytes)
 			return scan.Token(
 					TokenMap["FAILS"],
-					s[:len(s)-1],
+					strings.Trim(string(match.Bytes), "fF \t"),
 					match),
 				nil
 		},
 	)
 	lexer.Add(
-		[]byte(`[0-9]+[rR]`),
+		[]byte(`[0-9]+(\.[0-9]+)?(-[0-9]+|(,[0-9]*)+)?[rR]|[rR][ \t]*[0-9]+(\.[0-9]+)?(-[0-9]+|(,[0-9]*)+)?`),
 		func(scan *lexmachine.Scanner, match *machines.Match) (interface{}, error) {
-			s := string(match.Bytes)
 			return scan.Token(
 					TokenMap["REPS"],
-					s[:len(s)-1],
+					strings.Trim(string(match.Bytes), "rR \t"),
 					match),
 				nil
 		},
 	)
 	lexer.Add(
-		[]byte(`[0-9]+[sS]`),
+		[]byte(`[0-9]+(\.[0-9]+)?[sS]|[sS][ \t]*[0-9]+(\.[0-9]+)?`),
 		func(scan *lexmachine.Scanner, match *machines.Match) (interface{}, error) {
-			s := string(match.Bytes)
 			return scan.Token(
 					TokenMap["SETS"],
-					s[:len(s)-1],
+					strings.Trim(string(match.Bytes), "sS \t"),
+					match),
+				nil
+		},
+	)
+	lexer.Add(
+		[]byte(`[uU][nN][iI][tT][ \t]+[a-zA-Z]+`),
+		func(scan *lexmachine.Scanner, match *machines.Match) (interface{}, error) {
+			return scan.Token(
+					TokenMap["UNIT"],
+					strings.TrimSpace(string(match.Bytes)[4:]),
 					match),
 				nil
 		},
 	)
 	lexer.Add(
-		[]byte(`[0-9]*\.?[0-9]+`),
+		[]byte(`[aA][lL][iI][aA][sS][ \t]+[^=\n\r]+=[^\n\r]*`),
+		func(scan *lexmachine.Scanner, match *machines.Match) (interface{}, error) {
+			l, r := splitAlias(string(match.Bytes)[5:])
+			return scan.Token(
+					TokenMap["ALIAS"],
+					strings.TrimSpace(l)+"="+strings.TrimSpace(r),
+					match),
+				nil
+		},
+	)
+	lexer.Add(
+		[]byte(`[0-9]+[xX][0-9]+`),
+		func(scan *lexmachine.Scanner, match *machines.Match) (interface{}, error) {
+			return scan.Token(
+					TokenMap["SCHEME"],
+					strings.ToLower(string(match.Bytes)),
+					match),
+				nil
+		},
+	)
+	lexer.Add(
+		[]byte(`([0-9]*\.?[0-9]+ ?)?[bB][wW]([ \t]*(\+|-)[ \t]*[0-9]*\.?[0-9]+([a-zA-Z][a-zA-Z]+| `+unitSpellings()+`)?)?`),
+		func(scan *lexmachine.Scanner, match *machines.Match) (interface{}, error) {
+			s := strings.Join(strings.Fields(string(match.Bytes)), "")
+			i := strings.Index(strings.ToLower(s), "bw")
+			s = s[:i] + "BW" + s[i+2:]
+			if j := strings.IndexFunc(s[i+2:], unicode.IsLetter); j >= 0 {
+				s = s[:i+2+j] + " " + s[i+2+j:]
+			}
+			return scan.Token(TokenMap["LOAD"], s, match), nil
+		},
+	)
+	lexer.Add(
+		[]byte(`[sS][aA][mM][eE]|"`),
+		func(scan *lexmachine.Scanner, match *machines.Match) (interface{}, error) {
+			if !leadsLine(scan.Text, match) {
+				return nil, unmatched(scan, match)
+			}
+			return scan.Token(TokenMap["LOAD"], "same", match), nil
+		},
+	)
+	lexer.Add(
+		[]byte(`[0-9]*\.?[0-9]+(-[0-9]*\.?[0-9]+)?(%|[a-zA-Z][a-zA-Z]+| `+unitSpellings()+`)?`),
 		func(scan *lexmachine.Scanner, match *machines.Match) (interface{}, error) {
-			return scan.Token(TokenMap["LOAD"], string(match.Bytes), match), nil
+			s := string(match.Bytes)
+			i := strings.IndexFunc(s, unicode.IsLetter)
+			if i > 0 {
+				s = strings.TrimSpace(s[:i]) + " " + s[i:]
+			}
+			return scan.Token(TokenMap["LOAD"], s, match), nil
 		},
 	)
 	lexer.Add(
-		[]byte(`((\+\s*?)?\w+\s?)+:`),
+		[]byte("((\\+[ \t]*)?(\\w|[\x80-\xff])+[ \t]?)+:"),
 		func(scan *lexmachine.Scanner, match *machines.Match) (interface{}, error) {
 			s := strings.TrimSuffix(string(match.Bytes), ":")
 
@@ -160,6 +270,25 @@ func NewLexer() (Lexer, error) {
 			return scan.Token(tokType, s, match), nil
 		},
 	)
+	lexer.Add(
+		[]byte(`(\+|-)[ \t]*[0-9]*\.?[0-9]+[ \t]*[a-zA-Z][a-zA-Z]+([ \t]+[a-zA-Z][a-zA-Z]+)?`),
+		func(scan *lexmachine.Scanner, match *machines.Match) (interface{}, error) {
+			s := string(match.Bytes)
+			i := strings.IndexFunc(s, unicode.IsLetter)
+			words := strings.Fields(s[i:])
+			v := strings.Join(strings.Fields(s[:i]), "") + strings.Join(words, " ")
+			return scan.Token(TokenMap["MODIFIER"], v, match), nil
+		},
+	)
+	lexer.Add(
+		[]byte(`-`),
+		func(scan *lexmachine.Scanner, match *machines.Match) (interface{}, error) {
+			if !leadsLine(scan.Text, match) {
+				return nil, unmatched(scan, match)
+			}
+			return scan.Token(TokenMap["SET"], "-", match), nil
+		},
+	)
 	lexer.Add(
 		[]byte("( |\t|\n|\r)"),
 		func(scan *lexmachine.Scanner, match *machines.Match) (interface{}, error) {
@@ -175,7 +304,10 @@ func NewLexer() (Lexer, error) {
 	return Lexer{lexer}, nil
 }
 
-// Scan returns the next token
+// Scan returns the next token. Carriage returns are treated as whitespace, so
+// CRLF and mixed line endings never leak into token values. Text is read as
+// UTF-8: movement names may hold any letters, and token columns count runes
+// rather than bytes, though Span still reports byte offsets.
 func (lexer Lexer) Scan(text []byte) ([]*Token, error) {
 	scanner, err := lexer.l.Scanner(text)
 
@@ -195,5 +327,111 @@ func (lexer Lexer) Scan(text []byte) ([]*Token, error) {
 		tokens = append(tokens, token)
 	}
 
+	if !isASCII(text) {
+		runeColumns(text, tokens)
+	}
+
 	return tokens, nil
 }
+
+/* Private */
+
+// lexers holds compiled Lexers for reuse, since compiling the DFA costs far
+// more than a typical scan. A Lexer is only used by one goroutine at a time.
+var lexers = sync.Pool{
+	New: func() interface{} {
+		l, err := NewLexer()
+		if err != nil {
+			return nil
+		}
+		return &l
+	},
+}
+
+// scan tokenizes text with a pooled Lexer.
+func scan(text []byte) ([]*Token, error) {
+	l, ok := lexers.Get().(*Lexer)
+
+	if !ok {
+		lexer, err := NewLexer()
+		if err != nil {
+			return nil, err
+		}
+		l = &lexer
+	}
+	defer lexers.Put(l)
+
+	return l.Scan(text)
+}
+
+// unitSpellings matches the unit spellings of CanonicalUnit in any case, the
+// only units a load may be spaced apart from, so "100 kg" has a unit while
+// the word of "100 reps" does not.
+func unitSpellings() string {
+	var spellings []string
+	for u := range unitAliases {
+		var b strings.Builder
+		for _, r := range u {
+			b.WriteString("[" + string(unicode.ToLower(r)) + string(unicode.ToUpper(r)) + "]")
+		}
+		spellings = append(spellings, b.String())
+	}
+	sort.Strings(spellings)
+	return "(" + strings.Join(spellings, "|") + ")"
+}
+
+// leadsLine reports whether match stands alone at the start of its line,
+// ignoring indentation, as a set bullet or a "same" load must.
+func leadsLine(text []byte, match *machines.Match) bool {
+	for j := match.TC - 1; j >= 0 && text[j] != '\n' && text[j] != '\r'; j-- {
+		if text[j] != ' ' && text[j] != '\t' {
+			return false
+		}
+	}
+	if end := match.TC + len(match.Bytes); end < len(text) {
+		switch text[end] {
+		case ' ', '\t', '\n', '\r':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// unmatched reports match as input that could not be lexed.
+func unmatched(scan *lexmachine.Scanner, match *machines.Match) error {
+	return &machines.UnconsumedInput{
+		StartTC:     match.TC,
+		FailTC:      match.TC + len(match.Bytes),
+		StartLine:   match.StartLine,
+		StartColumn: match.StartColumn,
+		FailLine:    match.EndLine,
+		FailColumn:  match.EndColumn,
+		Text:        scan.Text,
+	}
+}
+
+func isASCII(text []byte) bool {
+	for _, b := range text {
+		if b >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// runeColumns converts the byte columns reported by lexmachine into rune
+// columns.
+func runeColumns(text []byte, tokens []*Token) {
+	for _, tok := range tokens {
+		t := tok.t
+		start := t.TC - (t.StartColumn - 1)
+		t.StartColumn = utf8.RuneCount(text[start:t.TC]) + 1
+
+		last := t.TC + len(t.Lexeme) - 1
+		if last < t.TC {
+			continue
+		}
+		t.EndColumn = utf8.RuneCount(text[last-(t.EndColumn-1) : last+1])
+	}
+}
@@ -147,3 +147,289 @@ func TestScan(t *testing.T) {
 		}
 	}
 }
+
+func TestScanLoadUnits(t *testing.T) {
+	lexer, err := NewLexer()
+
+	if err != nil {
+		t.Fatalf("Failed to init lexer: %q", err.Error())
+	}
+
+	tokens, err := lexer.Scan([]byte("m:\n100kg 2r\n225 lb 3s\n60 1f"))
+
+	if err != nil {
+		t.Fatalf("Failed to scan: %q", err.Error())
+	}
+
+	expected := []expectation{
+		expectation{"MOVEMENT", 4, "m", 1, 1, 1, 2},
+		expectation{"LOAD", 1, "100 kg", 2, 1, 2, 5},
+		expectation{"REPS", 7, "2", 2, 7, 2, 8},
+		expectation{"LOAD", 1, "225 lb", 3, 1, 3, 6},
+		expectation{"SETS", 8, "3", 3, 8, 3, 9},
+		expectation{"LOAD", 1, "60", 4, 1, 4, 2},
+		expectation{"FAILS", 2, "1", 4, 4, 4, 5},
+	}
+
+	if len(tokens) != len(expected) {
+		t.Fatalf("Expected %d tokens, got %d: %q", len(expected), len(tokens), tokens)
+	}
+
+	for idx, ex := range expected {
+		if err = ex.eq(tokens[idx]); err != nil {
+			t.Errorf("Mismatch!\n %q", err.Error())
+		}
+	}
+}
+
+func TestScanComments(t *testing.T) {
+	lexer, err := NewLexer()
+
+	if err != nil {
+		t.Fatalf("Failed to init lexer: %q", err.Error())
+	}
+
+	tokens, err := lexer.Scan([]byte("// whole line\nm: // trailing\n100 2r // heavy"))
+
+	if err != nil {
+		t.Fatalf("Failed to scan: %q", err.Error())
+	}
+
+	expected := []expectation{
+		expectation{"COMMENT", 9, "whole line", 1, 1, 1, 13},
+		expectation{"MOVEMENT", 4, "m", 2, 1, 2, 2},
+		expectation{"COMMENT", 9, "trailing", 2, 4, 2, 14},
+		expectation{"LOAD", 1, "100", 3, 1, 3, 3},
+		expectation{"REPS", 7, "2", 3, 5, 3, 6},
+		expectation{"COMMENT", 9, "heavy", 3, 8, 3, 15},
+	}
+
+	if len(tokens) != len(expected) {
+		t.Fatalf("Expected %d tokens, got %d: %q", len(expected), len(tokens), tokens)
+	}
+
+	for idx, ex := range expected {
+		if err = ex.eq(tokens[idx]); err != nil {
+			t.Errorf("Mismatch!\n %q", err.Error())
+		}
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	text := "@ 2020-01-01\nsquat:\n  100kg 5r"
+
+	tokens, err := Tokenize(text)
+
+	if err != nil {
+		t.Fatalf("Failed to tokenize: %q", err.Error())
+	}
+
+	expected := []struct {
+		name  string
+		start int
+		end   int
+	}{
+		{"DATE", 0, 12},
+		{"MOVEMENT", 13, 19},
+		{"LOAD", 22, 27},
+		{"REPS", 28, 30},
+	}
+
+	if len(tokens) != len(expected) {
+		t.Fatalf("Expected %d tokens, got %d: %q", len(expected), len(tokens), tokens)
+	}
+
+	for i, ex := range expected {
+		start, end := tokens[i].Span()
+
+		if tokens[i].Name() != ex.name || start != ex.start || end != ex.end {
+			t.Errorf("Mismatch for %q: %d-%d", tokens[i], start, end)
+		}
+
+		if text[start:end] == "" {
+			t.Errorf("Empty span for %q", tokens[i])
+		}
+	}
+}
+
+func TestScanCRLF(t *testing.T) {
+	lexer, err := NewLexer()
+
+	if err != nil {
+		t.Fatalf("Failed to init lexer: %q", err.Error())
+	}
+
+	tokens, err := lexer.Scan([]byte("@ 2020-01-01\r\n# key: value\r\n* note\r\nsquat:\r\n100\r\nbench press:\n200 2r\r\n"))
+
+	if err != nil {
+		t.Fatalf("Failed to scan: %q", err.Error())
+	}
+
+	expected := []expectation{
+		expectation{"DATE", 0, "2020-01-01", 1, 1, 1, 12},
+		expectation{"METADATA", 3, "key: value", 2, 1, 2, 12},
+		expectation{"NOTE", 6, "note", 3, 1, 3, 6},
+		expectation{"MOVEMENT", 4, "squat", 4, 1, 4, 6},
+		expectation{"LOAD", 1, "100", 5, 1, 5, 3},
+		expectation{"MOVEMENT", 4, "bench press", 6, 1, 6, 12},
+		expectation{"LOAD", 1, "200", 7, 1, 7, 3},
+		expectation{"REPS", 7, "2", 7, 5, 7, 6},
+	}
+
+	if len(tokens) != len(expected) {
+		t.Fatalf("Expected %d tokens, got %d: %q", len(expected), len(tokens), tokens)
+	}
+
+	for idx, ex := range expected {
+		if err = ex.eq(tokens[idx]); err != nil {
+			t.Errorf("Mismatch!\n %q", err.Error())
+		}
+	}
+}
+
+func TestScanScheme(t *testing.T) {
+	lexer, err := NewLexer()
+
+	if err != nil {
+		t.Fatalf("Failed to init lexer: %q", err.Error())
+	}
+
+	tokens, err := lexer.Scan([]byte("225 5x5\n3X10 1f\n"))
+
+	if err != nil {
+		t.Fatalf("Failed to scan: %q", err.Error())
+	}
+
+	expected := []expectation{
+		expectation{"LOAD", 1, "225", 1, 1, 1, 3},
+		expectation{"SCHEME", 10, "5x5", 1, 5, 1, 7},
+		expectation{"SCHEME", 10, "3x10", 2, 1, 2, 4},
+		expectation{"FAILS", 2, "1", 2, 6, 2, 7},
+	}
+
+	if len(tokens) != len(expected) {
+		t.Fatalf("Expected %d tokens, got %d: %q", len(expected), len(tokens), tokens)
+	}
+
+	for idx, ex := range expected {
+		if err = ex.eq(tokens[idx]); err != nil {
+			t.Errorf("Mismatch!\n %q", err.Error())
+		}
+	}
+}
+
+func TestScanUnicode(t *testing.T) {
+	tokens, err := Tokenize("Développé couché:\n  100 5r\n    * 🇫🇷 felt great\n+ Ñandú: 10r // olé")
+
+	if err != nil {
+		t.Fatalf("Failed to scan: %q", err.Error())
+	}
+
+	expected := []expectation{
+		expectation{"MOVEMENT", 4, "Développé couché", 1, 1, 1, 17},
+		expectation{"LOAD", 1, "100", 2, 3, 2, 5},
+		expectation{"REPS", 7, "5", 2, 7, 2, 8},
+		expectation{"NOTE", 6, "🇫🇷 felt great", 3, 5, 3, 19},
+		expectation{"MOVEMENT_SS", 5, "Ñandú", 4, 1, 4, 8},
+		expectation{"REPS", 7, "10", 4, 10, 4, 12},
+		expectation{"COMMENT", 9, "olé", 4, 14, 4, 19},
+	}
+
+	if len(tokens) != len(expected) {
+		t.Fatalf("Expected %d tokens, got %d: %q", len(expected), len(tokens), tokens)
+	}
+
+	for idx, ex := range expected {
+		if err = ex.eq(tokens[idx]); err != nil {
+			t.Errorf("Mismatch!\n %q", err.Error())
+		}
+	}
+
+	session, err := ParseString("Développé: 100 5.5r\n    * 💪")
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if session.Movements[0].Name != "Développé" || session.Movements[0].Performances[0].Notes[0] != "💪" {
+		t.Errorf("Unexpected session: %v", session)
+	}
+
+	if len(session.Errors) != 1 || session.Errors[0].(*ParseError).Col != 16 {
+		t.Errorf("Expected an error in rune columns: %v", session.Errors)
+	}
+}
+
+func TestLexerReuse(t *testing.T) {
+	lexer, err := NewLexer()
+
+	if err != nil {
+		t.Fatalf("Failed to init lexer: %q", err.Error())
+	}
+
+	for _, text := range []string{"squat:\n  100 5r", "@ 2020-01-01", "bench: 80"} {
+		tokens, err := lexer.Scan([]byte(text))
+		expected, _ := Tokenize(text)
+
+		if err != nil || len(tokens) != len(expected) || tokens[0].Value() != expected[0].Value() {
+			t.Errorf("Unexpected tokens for %q on reuse: %v %v", text, tokens, err)
+		}
+	}
+}
+
+func BenchmarkLexerFresh(b *testing.B) {
+	text := largeLog(20)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		lexer, err := NewLexer()
+		if err != nil {
+			b.Fatalf("Failed to init lexer: %q", err)
+		}
+
+		if _, err := lexer.Scan(text); err != nil {
+			b.Fatalf("Failed to scan: %q", err)
+		}
+	}
+}
+
+func BenchmarkLexerReuse(b *testing.B) {
+	text := largeLog(20)
+	lexer, err := NewLexer()
+
+	if err != nil {
+		b.Fatalf("Failed to init lexer: %q", err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := lexer.Scan(text); err != nil {
+			b.Fatalf("Failed to scan: %q", err)
+		}
+	}
+}
+
+func TestLexerSpacedUnits(t *testing.T) {
+	for text, expected := range map[string]string{
+		"100kg":     "100 kg",
+		"100 Kilos": "100 Kilos",
+		"60stone":   "60 stone",
+		"BW+10 lbs": "BW+10 lbs",
+	} {
+		tokens, err := Tokenize(text)
+
+		if err != nil || len(tokens) != 1 || tokens[0].Value() != expected {
+			t.Errorf("Unexpected tokens for %q: %v %v", text, tokens, err)
+		}
+	}
+
+	tokens, err := Tokenize("100 reps")
+
+	if err == nil || len(tokens) != 1 || tokens[0].Value() != "100" {
+		t.Errorf("Expected a spaced word other than a unit to stay out of the load: %v %v", tokens, err)
+	}
+}
@@ -0,0 +1,99 @@
+package traindown
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/timtadh/lexmachine/machines"
+)
+
+// Severity grades a Diagnostic.
+type Severity int
+
+// Severities reported by Lint.
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	if s == SeverityWarning {
+		return "warning"
+	}
+	return "error"
+}
+
+// Diagnostic is a problem found by Lint. Line and Col are 1-based, or 0 when
+// the problem has no position in the source.
+type Diagnostic struct {
+	Severity Severity `json:"severity"`
+	Line     int      `json:"line"`
+	Col      int      `json:"col"`
+	Message  string   `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%d:%d: %s: %s", d.Line, d.Col, d.Severity, d.Message)
+}
+
+/* Public */
+
+// Lint parses txt as ParseSessions does, with every optional check enabled,
+// and returns the problems found in order of position. Parse errors keep the
+// severity of their ParseError, while failures of DefaultRules are warnings,
+// placed at the start of the offending Movement or Performance. Input that
+// could not be scanned at all yields a single error where the unmatched text
+// starts.
+func Lint(txt string) []Diagnostic {
+	sessions, err := ParseSessions(txt,
+		WithDuplicateKeyWarnings(true),
+		WithPercentValidation(true),
+		WithSourceSpans(true),
+		WithUnknownTokenError(true))
+
+	if err != nil {
+		return []Diagnostic{diagnose(err, SeverityError)}
+	}
+
+	var ds []Diagnostic
+	for _, s := range sessions {
+		for _, err := range s.Errors {
+			sev := SeverityError
+			if IsWarning(err) {
+				sev = SeverityWarning
+			}
+			ds = append(ds, diagnose(err, sev))
+		}
+
+		for _, err := range s.Validate() {
+			ds = append(ds, diagnose(err, SeverityWarning))
+		}
+	}
+
+	sort.SliceStable(ds, func(i, j int) bool {
+		if ds[i].Line != ds[j].Line {
+			return ds[i].Line < ds[j].Line
+		}
+		return ds[i].Col < ds[j].Col
+	})
+
+	return ds
+}
+
+/* Private */
+
+// diagnose places err at the position it carries, if any.
+func diagnose(err error, sev Severity) Diagnostic {
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		return Diagnostic{sev, pe.Line, pe.Col, pe.Msg}
+	}
+
+	var ui *machines.UnconsumedInput
+	if errors.As(err, &ui) {
+		return Diagnostic{sev, ui.StartLine, ui.StartColumn, err.Error()}
+	}
+
+	return Diagnostic{Severity: sev, Message: err.Error()}
+}
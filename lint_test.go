@@ -0,0 +1,51 @@
+package traindown
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	txt := `@ 2021-03-04
+# unit: lb
+
+squat:
+  135 5r
+  # rpe: 8
+  # rpe: 9
+  225 0r
+
+bench:
+  110% 3r
+
+squat:
+  100 5.5r
+`
+
+	got := Lint(txt)
+	want := []Diagnostic{
+		{SeverityWarning, 7, 3, `Duplicate metadata key: "rpe"`},
+		{SeverityWarning, 8, 1, `squat #2: Zero reps at 225`},
+		{SeverityWarning, 11, 3, `Percent of max over 100: "110%"`},
+		{SeverityWarning, 13, 1, `Duplicate movement: "squat"`},
+		{SeverityWarning, 14, 1, `squat #1: Zero reps at 100`},
+		{SeverityError, 14, 7, `Failed to parse "reps": "5.5"`},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected diagnostics:\n%v\n%v", got, want)
+	}
+
+	if s := got[5].String(); s != `14:7: error: Failed to parse "reps": "5.5"` {
+		t.Errorf("Unexpected string: %q", s)
+	}
+
+	if ds := Lint("@ 2021-03-04\nsquat:\n  100 5r\n"); len(ds) != 0 {
+		t.Errorf("Unexpected diagnostics for clean input: %v", ds)
+	}
+
+	ds := Lint("@ 2021-03-04\nsquat:\n  100 5r\n  heavy 5r\n")
+	if len(ds) != 1 || ds[0].Severity != SeverityError || ds[0].Line != 4 || ds[0].Col != 3 {
+		t.Errorf("Unexpected diagnostics for unscannable input: %v", ds)
+	}
+}
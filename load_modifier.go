@@ -0,0 +1,164 @@
+package traindown
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Kinds of accommodating resistance understood as a LoadModifier.
+const (
+	Band  = "band"
+	Chain = "chain"
+)
+
+// LoadModifier is accommodating resistance added to, or with a negative
+// Magnitude taken from, the bar load, as in "225 +40band" or "315 -50chain".
+// Its Magnitude is in the unit of its Performance.
+type LoadModifier struct {
+	Type      string  `json:"type"`
+	Magnitude float32 `json:"magnitude"`
+}
+
+// ParseLoadModifier reads a modifier written as a signed magnitude followed by
+// its type, such as "+40band" or "-50 chains". The type is case insensitive
+// and may be plural.
+func ParseLoadModifier(s string) (LoadModifier, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+	})
+
+	if i < 1 {
+		return LoadModifier{}, fmt.Errorf("Failed to parse %q: %q", "load modifier", s)
+	}
+
+	t := strings.TrimSuffix(strings.ToLower(s[i:]), "s")
+	if t != Band && t != Chain {
+		return LoadModifier{}, fmt.Errorf("Unknown load modifier: %q", s)
+	}
+
+	f, err := strconv.ParseFloat(strings.Join(strings.Fields(s[:i]), ""), 32)
+	if err != nil {
+		return LoadModifier{}, fmt.Errorf("Failed to parse %q: %q", "load modifier", s)
+	}
+
+	return LoadModifier{Type: t, Magnitude: float32(f)}, nil
+}
+
+func (lm LoadModifier) String() string {
+	sign := "+"
+	if lm.Magnitude < 0 {
+		sign = "-"
+	}
+	return sign + formatLoad(abs(lm.Magnitude)) + lm.Type
+}
+
+// LoadComponent is one part of a load summed from several, such as the
+// collars of "100kg + 20kg collars". Its Unit is empty when not stated.
+type LoadComponent struct {
+	Load  float32 `json:"load"`
+	Unit  string  `json:"unit,omitempty"`
+	Label string  `json:"label,omitempty"`
+}
+
+func (lc LoadComponent) String() string {
+	s := formatLoad(lc.Load)
+	if lc.Unit != "" {
+		s += lc.Unit
+	}
+	if lc.Label != "" {
+		s += " " + lc.Label
+	}
+	return s
+}
+
+// EffectiveLoad is the Load, plus the Magnitude of each LoadModifier when
+// withModifiers is set.
+func (p Performance) EffectiveLoad(withModifiers bool) float32 {
+	l := p.Load
+	if withModifiers {
+		for _, lm := range p.LoadModifiers {
+			l += lm.Magnitude
+		}
+	}
+	return l
+}
+
+/* Private */
+
+func abs(f float32) float32 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
+
+// parseLoadComponent reads a signed load in a known unit with an optional
+// label, such as "+20kg collars". Anything else is left to
+// ParseLoadModifier.
+func parseLoadComponent(s string) (LoadComponent, bool) {
+	i := strings.IndexFunc(s, unicode.IsLetter)
+	if i < 1 {
+		return LoadComponent{}, false
+	}
+
+	words := strings.Fields(s[i:])
+	if _, ok := CanonicalUnit(words[0]); !ok {
+		return LoadComponent{}, false
+	}
+
+	f, err := strconv.ParseFloat(s[:i], 32)
+	if err != nil {
+		return LoadComponent{}, false
+	}
+
+	lc := LoadComponent{Load: float32(f), Unit: words[0]}
+	if len(words) > 1 {
+		lc.Label = words[1]
+	}
+	return lc, true
+}
+
+// addLoadComponent records lc as a part of the Load, after the Load as
+// written. The parts are summed by sumLoadComponents once the unit of the
+// Performance is known.
+func (p *Performance) addLoadComponent(lc LoadComponent) {
+	if len(p.LoadComponents) == 0 {
+		base := LoadComponent{Load: p.Load}
+		if p.hasUnit() {
+			base.Unit = p.Unit
+		}
+		p.LoadComponents = append(p.LoadComponents, base)
+	}
+	p.LoadComponents = append(p.LoadComponents, lc)
+}
+
+// sumLoadComponents adds each of the LoadComponents after the first to the
+// Load, converted to the Unit of the Performance. A Performance without a
+// unit takes that of its second component. The errors hold one entry for each
+// component after the first, nil unless it could not be converted and was
+// left out of the Load.
+func (p *Performance) sumLoadComponents() []error {
+	if len(p.LoadComponents) < 2 {
+		return nil
+	}
+
+	if !p.hasUnit() {
+		p.Unit = p.LoadComponents[1].Unit
+	}
+
+	errs := make([]error, len(p.LoadComponents)-1)
+	for i, lc := range p.LoadComponents[1:] {
+		l, err := ConvertLoad(lc.Load, lc.Unit, p.Unit)
+		if err != nil {
+			errs[i] = fmt.Errorf("Failed to convert load component %q: %q", lc.String(), err)
+			continue
+		}
+		p.Load += l
+	}
+
+	p.LoadMin, p.LoadMax = p.Load, p.Load
+	return errs
+}
@@ -0,0 +1,198 @@
+package traindown
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseLoadModifier(t *testing.T) {
+	cases := map[string]LoadModifier{
+		"+40band":    {Band, 40},
+		"-50 Chains": {Chain, -50},
+		"+ 12.5band": {Band, 12.5},
+	}
+
+	for s, expected := range cases {
+		lm, err := ParseLoadModifier(s)
+
+		if err != nil || lm != expected {
+			t.Errorf("Failed to parse %q: %v %v", s, lm, err)
+		}
+	}
+
+	for _, s := range []string{"+40sled", "band", "+band"} {
+		if _, err := ParseLoadModifier(s); err == nil {
+			t.Errorf("Expected an error for %q", s)
+		}
+	}
+
+	if s := (LoadModifier{Chain, -50}).String(); s != "-50chain" {
+		t.Errorf("Unexpected string: %q", s)
+	}
+}
+
+func TestParseLoadModifiers(t *testing.T) {
+	session, err := ParseString(`
+    squat:
+      225 +40band -20chain 5r 3s
+      315 -50chain
+      bw +10bands 8r
+      100 +5sled
+
+    bench:
+      +20band`)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	ps := session.Movements[0].Performances
+
+	if len(ps) != 4 {
+		t.Fatalf("Expected 4 performances, got %v", ps)
+	}
+
+	p := ps[0]
+	if p.Load != 225 || p.Reps != 5 || p.Sets != 3 ||
+		!reflect.DeepEqual(p.LoadModifiers, []LoadModifier{{Band, 40}, {Chain, -20}}) {
+		t.Errorf("Unexpected performance: %v", p)
+	}
+
+	if l := p.EffectiveLoad(true); l != 245 {
+		t.Errorf("Unexpected effective load: %v", l)
+	}
+
+	if l := p.EffectiveLoad(false); l != 225 {
+		t.Errorf("Unexpected effective load without modifiers: %v", l)
+	}
+
+	if l := ps[1].EffectiveLoad(true); l != 265 {
+		t.Errorf("Unexpected effective load: %v", l)
+	}
+
+	if len(ps[2].LoadModifiers) != 1 || ps[2].Reps != 8 || !ps[2].Unloaded() {
+		t.Errorf("Unexpected bodyweight performance: %v", ps[2])
+	}
+
+	if len(session.Errors) != 2 ||
+		session.Errors[0].Error() != `6:11: Unknown load modifier: "+5sled"` ||
+		session.Errors[1].Error() != `9:7: Load modifier before any performance: "+20band"` {
+		t.Errorf("Unexpected errors: %v", session.Errors)
+	}
+
+	session.Errors = nil
+	b, err := session.Marshal()
+
+	if err != nil {
+		t.Fatalf("Failed to marshal: %q", err)
+	}
+
+	again, err := ParseByte(b)
+
+	if err != nil {
+		t.Fatalf("Failed to parse marshaled session: %q", err)
+	}
+
+	if d := session.Mismatch(again); d != "" {
+		t.Errorf("Round trip mismatch: %s\n\n%s", d, b)
+	}
+
+	if c := p.Clone(); !reflect.DeepEqual(c.LoadModifiers, p.LoadModifiers) {
+		t.Errorf("Failed to clone modifiers: %v", c)
+	}
+}
+
+func TestParseLoadComponents(t *testing.T) {
+	txt := `@ 2021-03-04
+log:
+  100kg + 20kg collars + 10lb 1r
+  50 + 2.5kg 3r
+  60kg - 5kg 2r
+  60stone + 5kg 2r
+`
+
+	session, err := ParseString(txt)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	ps := session.Movements[0].Performances
+
+	if !floatEqual(ps[0].Load, 124.5359) || ps[0].Unit != "kg" || ps[0].Reps != 1 {
+		t.Errorf("Unexpected load: %v %s", ps[0].Load, ps[0].Unit)
+	}
+
+	want := []LoadComponent{{100, "kg", ""}, {20, "kg", "collars"}, {10, "lb", ""}}
+	if !reflect.DeepEqual(ps[0].LoadComponents, want) {
+		t.Errorf("Unexpected components: %v", ps[0].LoadComponents)
+	}
+
+	if ps[1].Load != 52.5 || ps[1].Unit != "kg" {
+		t.Errorf("Failed to adopt the component unit: %v %s", ps[1].Load, ps[1].Unit)
+	}
+
+	if ps[2].Load != 55 || ps[2].LoadComponents[1].Load != -5 {
+		t.Errorf("Failed to subtract a component: %v", ps[2])
+	}
+
+	if len(session.Errors) != 1 || !strings.HasPrefix(session.Errors[0].Error(), `6:11: Failed to convert load component "5kg"`) {
+		t.Errorf("Unexpected errors: %v", session.Errors)
+	}
+
+	if ps[3].Load != 60 || len(ps[3].LoadComponents) != 2 {
+		t.Errorf("Unexpected unconverted load: %v", ps[3])
+	}
+
+	session.Errors = nil
+	b, err := session.Marshal()
+
+	if err != nil {
+		t.Fatalf("Failed to marshal: %q", err)
+	}
+
+	again, err := ParseByte(b)
+
+	if err != nil {
+		t.Fatalf("Failed to parse marshaled session: %q", err)
+	}
+
+	again.Errors = nil
+	if d := session.Mismatch(again); d != "" {
+		t.Errorf("Round trip mismatch: %s\n\n%s", d, b)
+	}
+
+	if c := ps[0].Clone(); !reflect.DeepEqual(c.LoadComponents, ps[0].LoadComponents) {
+		t.Errorf("Failed to clone components: %v", c)
+	}
+}
+
+func TestParseLoadComponentsInheritUnit(t *testing.T) {
+	session, err := ParseString(`@ 2021-03-04
+# unit: lb
+log:
+  100 + 20kg collars 1r
+`)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	p := session.Movements[0].Performances[0]
+
+	if !floatEqual(p.Load, 144.0925) || p.Unit != "lb" || len(session.Errors) != 0 {
+		t.Errorf("Failed to sum in the inherited unit: %v %s %v", p.Load, p.Unit, session.Errors)
+	}
+
+	c, err := p.ConvertTo("kg")
+
+	if err != nil {
+		t.Fatalf("Failed to convert: %q", err)
+	}
+
+	want := []LoadComponent{{45.359, "kg", ""}, {20, "kg", "collars"}}
+	if !componentsEqual(c.LoadComponents, want) {
+		t.Errorf("Failed to convert components: %v", c.LoadComponents)
+	}
+}
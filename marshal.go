@@ -0,0 +1,187 @@
+package traindown
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Marshal renders the Session back into a Traindown document. Notes and
+// metadata are escaped with EscapeChar where needed to read back identically.
+func (s *Session) Marshal() ([]byte, error) {
+	var b strings.Builder
+
+	if !s.Date.IsZero() {
+		b.WriteString("@ ")
+		b.WriteString(formatDate(s.Date))
+		b.WriteString("\n")
+	}
+
+	if s.Title != "" {
+		b.WriteString("# title: ")
+		b.WriteString(escapeValue(s.Title))
+		b.WriteString("\n")
+	}
+
+	writeMetadata(&b, "", s.Metadata, s.DefaultUnit)
+	writeNotes(&b, "", s.Notes)
+
+	for _, m := range s.Movements {
+		b.WriteString("\n")
+		if m.SuperSet {
+			b.WriteString("+ ")
+		}
+		b.WriteString(m.Name)
+		b.WriteString(":\n")
+
+		writeMetadata(&b, "  ", m.Metadata, m.DefaultUnit)
+		writeNotes(&b, "  ", m.Notes)
+
+		inherited := s.DefaultUnit
+		if m.DefaultUnit != "" {
+			inherited = m.DefaultUnit
+		}
+
+		for _, p := range m.Performances {
+			b.WriteString("  ")
+			if p.RelativeLoad != "" {
+				b.WriteString(p.RelativeLoad)
+			} else if p.PrescribedPercent {
+				b.WriteString(formatLoad(p.PercentOfMax))
+				b.WriteString("%")
+			} else if len(p.LoadComponents) > 0 {
+				for i, lc := range p.LoadComponents {
+					if i > 0 && lc.Load < 0 {
+						b.WriteString(" - ")
+						lc.Load = -lc.Load
+					} else if i > 0 {
+						b.WriteString(" + ")
+					}
+					b.WriteString(lc.String())
+				}
+			} else if p.LoadMax > p.LoadMin {
+				b.WriteString(formatLoad(p.LoadMin))
+				b.WriteString("-")
+				b.WriteString(formatLoad(p.LoadMax))
+			} else {
+				b.WriteString(formatLoad(p.Load))
+			}
+			for _, lm := range p.LoadModifiers {
+				b.WriteString(" ")
+				b.WriteString(lm.String())
+			}
+			if p.Fails != 0 {
+				b.WriteString(" ")
+				b.WriteString(strconv.Itoa(p.Fails))
+				b.WriteString("f")
+			}
+			if len(p.SetDetails) > 0 {
+				b.WriteString("\n")
+			} else {
+				writeReps(&b, p)
+			}
+
+			unit := ""
+			if p.Unit != inherited && p.Unit != "unknown unit" {
+				unit = p.Unit
+			}
+
+			writeMetadata(&b, "    ", p.Metadata, unit)
+			writeNotes(&b, "    ", p.Notes)
+
+			for _, set := range p.SetDetails {
+				b.WriteString("    - ")
+				b.WriteString(strconv.Itoa(set.Reps))
+				b.WriteString("r")
+				if set.Fails != 0 {
+					b.WriteString(" ")
+					b.WriteString(strconv.Itoa(set.Fails))
+					b.WriteString("f")
+				}
+				b.WriteString("\n")
+
+				writeMetadata(&b, "      ", set.Metadata, "")
+				writeNotes(&b, "      ", set.Notes)
+			}
+		}
+	}
+
+	return []byte(b.String()), nil
+}
+
+/* Private */
+
+func formatDate(d time.Time) string {
+	layout := "2006-01-02"
+	if d.Hour() != 0 || d.Minute() != 0 || d.Second() != 0 {
+		layout = "2006-01-02 15:04:05"
+	}
+	if d.Location() != time.UTC {
+		layout += " -0700"
+	}
+	return d.Format(layout)
+}
+
+func formatLoad(l float32) string {
+	return strconv.FormatFloat(float64(l), 'f', -1, 32)
+}
+
+func writeReps(b *strings.Builder, p *Performance) {
+	b.WriteString(" ")
+	if p.RepRange != nil {
+		b.WriteString(p.RepRange.String())
+	} else if p.PerSetReps != nil {
+		for i, r := range p.PerSetReps {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			b.WriteString(strconv.Itoa(r))
+		}
+	} else {
+		b.WriteString(strconv.Itoa(p.Reps))
+	}
+	b.WriteString("r")
+	if p.Sets != 1 && p.PerSetReps == nil {
+		b.WriteString(" ")
+		b.WriteString(strconv.Itoa(p.Sets))
+		b.WriteString("s")
+	}
+	b.WriteString("\n")
+}
+
+func writeMetadata(b *strings.Builder, indent string, md Metadata, unit string) {
+	if unit != "" {
+		b.WriteString(indent)
+		b.WriteString("# unit: ")
+		b.WriteString(unit)
+		b.WriteString("\n")
+	}
+
+	keys := make([]string, 0, len(md))
+	for k := range md {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		b.WriteString(indent)
+		b.WriteString("# ")
+		b.WriteString(escapeKey(k))
+		if md[k] != true {
+			b.WriteString(": ")
+			b.WriteString(escapeValue(fmt.Sprint(md[k])))
+		}
+		b.WriteString("\n")
+	}
+}
+
+func writeNotes(b *strings.Builder, indent string, notes []string) {
+	for _, n := range notes {
+		b.WriteString(indent)
+		b.WriteString("* ")
+		b.WriteString(escapeNote(n))
+		b.WriteString("\n")
+	}
+}
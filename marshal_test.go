@@ -0,0 +1,140 @@
+package traindown
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMarshal(t *testing.T) {
+	text := `
+    @ 1/1/20 1:23
+    # Title: Heavy, day
+    # key: value
+    # unit: session
+    * session note
+
+    movement:
+      # unit: movement
+      100 1r 1f 1s
+        * performance note
+        # performance key: performance value
+
+    + another:
+      * movement note
+      # movement key: movement value
+      200.1
+      200.2 5r 2s
+        # unit: performance`
+
+	session, err := ParseString(text)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	b, err := session.Marshal()
+
+	if err != nil {
+		t.Fatalf("Failed to marshal: %q", err)
+	}
+
+	out := string(b)
+
+	for _, want := range []string{
+		"@ 2020-01-01 01:23:00\n",
+		"# title: Heavy\\, day\n",
+		"# unit: session\n",
+		"\n+ another:\n",
+		"  100 1f 1r\n",
+		"  200.1 1r\n",
+		"  200.2 5r 2s\n",
+		"    # unit: performance\n",
+		"    # performance key: performance value\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Missing %q in output:\n%s", want, out)
+		}
+	}
+
+	if strings.Contains(out, "100.0") {
+		t.Errorf("Integer load rendered with trailing zero:\n%s", out)
+	}
+
+	again, err := ParseByte(b)
+
+	if err != nil {
+		t.Fatalf("Failed to parse marshaled session: %q", err)
+	}
+
+	if !reflect.DeepEqual(session, again) {
+		t.Errorf("Round trip mismatch.\n\nGot:\n%v\n\nExpected:\n%v", again, session)
+	}
+}
+
+func TestMarshalEmpty(t *testing.T) {
+	b, err := NewSession().Marshal()
+
+	if err != nil {
+		t.Fatalf("Failed to marshal: %q", err)
+	}
+
+	if len(b) != 0 {
+		t.Errorf("Expected empty output, got %q", b)
+	}
+}
+
+func TestMarshalPrescribedPercent(t *testing.T) {
+	session, err := ParseString("movement:\n  80% 5r")
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	b, err := session.Marshal()
+
+	if err != nil {
+		t.Fatalf("Failed to marshal: %q", err)
+	}
+
+	if !strings.Contains(string(b), "  80% 5r\n") {
+		t.Errorf("Prescribed percent not preserved:\n%s", b)
+	}
+}
+
+func TestMarshalEscaping(t *testing.T) {
+	session := NewSession()
+	session.Notes = []string{"* # @ : | all in a note", "two\nlines", `back\slash`}
+	session.Metadata["odd: key"] = "a, b: c"
+	m := NewMovement()
+	m.Name = "movement"
+	p := NewPerformance()
+	p.Load = 100
+	p.Notes = []string{"* # @ :"}
+	m.Performances = []*Performance{p}
+	session.Movements = []*Movement{m}
+
+	b, err := session.Marshal()
+
+	if err != nil {
+		t.Fatalf("Failed to marshal: %q", err)
+	}
+
+	again, err := ParseByte(b)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if !reflect.DeepEqual(again.Notes, session.Notes) {
+		t.Errorf("Session notes mismatch: %q\n%s", again.Notes, b)
+	}
+
+	if again.Metadata["odd: key"] != "a, b: c" || len(again.Metadata) != 1 {
+		t.Errorf("Session metadata mismatch: %q\n%s", again.Metadata, b)
+	}
+
+	if got := again.Movements[0].Performances[0].Notes; !reflect.DeepEqual(got, p.Notes) {
+		t.Errorf("Performance notes mismatch: %q\n%s", got, b)
+	}
+}
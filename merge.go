@@ -0,0 +1,57 @@
+package traindown
+
+import (
+	"fmt"
+	"time"
+)
+
+// Merge combines s and other into a new Session. Movements are cloned, with
+// those from other following those from s, and renumbered. On a metadata
+// conflict the value from s wins. Notes and errors are appended. Merge
+// refuses sessions whose dates are more than a day apart; see MergeForce.
+func (s *Session) Merge(other *Session) (*Session, error) {
+	d := s.Date.Sub(other.Date)
+	if d < 0 {
+		d = -d
+	}
+
+	if d > 24*time.Hour {
+		return nil, fmt.Errorf("Refusing to merge sessions from %v and %v", s.Date, other.Date)
+	}
+
+	return s.MergeForce(other), nil
+}
+
+// MergeForce is Merge without the date check. The merged Session takes the
+// date of s.
+func (s *Session) MergeForce(other *Session) *Session {
+	merged := NewSession()
+	merged.Date = s.Date
+	merged.DefaultUnit = s.DefaultUnit
+	if merged.DefaultUnit == "" {
+		merged.DefaultUnit = other.DefaultUnit
+	}
+	merged.Title = s.Title
+	if merged.Title == "" {
+		merged.Title = other.Title
+	}
+
+	for _, src := range []*Session{other, s} {
+		for k, v := range src.Metadata {
+			merged.Metadata[k] = v
+		}
+	}
+
+	merged.Notes = append(append(merged.Notes, s.Notes...), other.Notes...)
+	merged.Errors = append(append(merged.Errors, s.Errors...), other.Errors...)
+
+	for _, src := range []*Session{s, other} {
+		for _, m := range src.Movements {
+			c := m.Clone()
+			c.Sequence = len(merged.Movements) + 1
+			merged.Movements = append(merged.Movements, c)
+		}
+	}
+
+	return merged
+}
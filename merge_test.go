@@ -0,0 +1,74 @@
+package traindown
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMerge(t *testing.T) {
+	am, err := ParseString("@ 2020-01-01 06:00\n# mood: good\n# place: home\n* early\nsquat:\n  100\n  bench:\n  50")
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	pm, err := ParseString("@ 2020-01-01 18:00\n# mood: bad\n# bodyweight: 80\n* late\ndeadlift:\n200")
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	merged, err := am.Merge(pm)
+
+	if err != nil {
+		t.Fatalf("Failed to merge: %q", err)
+	}
+
+	if merged.Date != am.Date {
+		t.Errorf("Incorrect date: %v", merged.Date)
+	}
+
+	names := []string{"squat", "bench", "deadlift"}
+
+	if len(merged.Movements) != len(names) {
+		t.Fatalf("Expected %d movements, got %d", len(names), len(merged.Movements))
+	}
+
+	for i, m := range merged.Movements {
+		if m.Name != names[i] || m.Sequence != i+1 {
+			t.Errorf("Incorrect movement %d: %q (%d)", i, m.Name, m.Sequence)
+		}
+	}
+
+	if pm.Movements[0].Sequence != 1 {
+		t.Errorf("Merge renumbered the original movement")
+	}
+
+	if merged.Metadata["mood"] != "good" ||
+		merged.Metadata["place"] != "home" ||
+		merged.Metadata["bodyweight"] != "80" {
+		t.Errorf("Incorrect metadata: %v", merged.Metadata)
+	}
+
+	if len(merged.Notes) != 2 || merged.Notes[0] != "early" || merged.Notes[1] != "late" {
+		t.Errorf("Incorrect notes: %v", merged.Notes)
+	}
+}
+
+func TestMergeDifferentDates(t *testing.T) {
+	a := NewSession()
+	a.Date = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := NewSession()
+	b.Date = time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC)
+	b.Movements = []*Movement{&Movement{Name: "squat", Sequence: 1}}
+
+	if _, err := a.Merge(b); err == nil {
+		t.Errorf("Expected an error merging sessions two days apart")
+	}
+
+	merged := a.MergeForce(b)
+
+	if merged.Date != a.Date || len(merged.Movements) != 1 {
+		t.Errorf("Failed to force merge: %v", merged)
+	}
+}
@@ -1,4 +1,183 @@
 package traindown
 
-// Metadata is key value pairs.
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Metadata is key value pairs. A key given without a value, such as
+// "# deload", is a flag and holds true.
 type Metadata map[string]interface{}
+
+// Has reports whether key is set. Flags such as "# deload" hold true, so Has
+// tells whether one was given.
+func (m Metadata) Has(key string) bool {
+	_, ok := m[key]
+	return ok
+}
+
+// Set stores v at key, allocating the map when it is nil, as it is for parsed
+// elements given no metadata.
+func (m *Metadata) Set(key string, v interface{}) {
+	if *m == nil {
+		*m = make(Metadata)
+	}
+	(*m)[key] = v
+}
+
+// Bool reads key as a bool. Strings are parsed with strconv.ParseBool.
+func (m Metadata) Bool(key string) (bool, bool) {
+	switch v := m[key].(type) {
+	case bool:
+		return v, true
+	case string:
+		b, err := strconv.ParseBool(strings.TrimSpace(v))
+		return b, err == nil
+	}
+	return false, false
+}
+
+// Float reads key as a float32. Strings are parsed on demand.
+func (m Metadata) Float(key string) (float32, bool) {
+	switch v := m[key].(type) {
+	case float32:
+		return v, true
+	case float64:
+		return float32(v), true
+	case int:
+		return float32(v), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 32)
+		return float32(f), err == nil
+	}
+	return 0, false
+}
+
+// Int reads key as an int. Strings are parsed as base 10, so "010" is 10.
+// Floats are only accepted when they are whole.
+func (m Metadata) Int(key string) (int, bool) {
+	switch v := m[key].(type) {
+	case int:
+		return v, true
+	case float32:
+		return int(v), float32(int(v)) == v
+	case float64:
+		return int(v), float64(int(v)) == v
+	case string:
+		i, err := strconv.Atoi(strings.TrimSpace(v))
+		return i, err == nil
+	}
+	return 0, false
+}
+
+// List reads key as a comma separated list, trimming each item and dropping
+// empty ones.
+func (m Metadata) List(key string) ([]string, bool) {
+	v, ok := m.String(key)
+	if !ok {
+		return nil, false
+	}
+
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items, true
+}
+
+// String reads key as a string, formatting values of any other type.
+func (m Metadata) String(key string) (string, bool) {
+	v, ok := m[key]
+	if !ok {
+		return "", false
+	}
+	if s, ok := v.(string); ok {
+		return s, true
+	}
+	return fmt.Sprint(v), true
+}
+
+// NormalizeKey lowercases a key and joins its words with underscores, so
+// "Body Weight", "body-weight", and "body_weight" all become "body_weight".
+func NormalizeKey(key string) string {
+	words := strings.FieldsFunc(strings.ToLower(key), func(r rune) bool {
+		return unicode.IsSpace(r) || r == '_' || r == '-'
+	})
+	return strings.Join(words, "_")
+}
+
+/* Private */
+
+// lookup finds the first key that normalizes to one of names, as by
+// NormalizeKey.
+func (m Metadata) lookup(names ...string) (string, bool) {
+	for k := range m {
+		nk := NormalizeKey(k)
+		for _, n := range names {
+			if nk == n {
+				return k, true
+			}
+		}
+	}
+	return "", false
+}
+
+// splitPairs splits a metadata line holding several comma separated pairs,
+// such as "rpe: 8, tempo: 3010". A comma only starts a new pair when what
+// follows it is a key, meaning text containing a letter before a colon.
+// Otherwise the comma is literal, so "muscles: chest, triceps" and
+// "at: 10:00, 11:00" each remain a single pair.
+func splitPairs(line string) []string {
+	var pairs []string
+	start := 0
+	for i := 0; i < len(line); i++ {
+		if line[i] == EscapeChar {
+			i++
+			continue
+		}
+		if line[i] != ',' || !startsPair(line[i+1:]) {
+			continue
+		}
+		pairs = append(pairs, strings.TrimSpace(line[start:i]))
+		start = i + 1
+	}
+	return append(pairs, strings.TrimSpace(line[start:]))
+}
+
+func startsPair(s string) bool {
+	i := indexUnescaped(s, ":,")
+	if i < 0 || s[i] != ':' {
+		return false
+	}
+	return strings.IndexFunc(s[:i], unicode.IsLetter) >= 0
+}
+
+// coerceValue converts a raw metadata value into a bool, int, or float64 when
+// it is unambiguously one. Numbers with a leading zero, such as "010", are
+// left as strings since they are more likely identifiers than quantities, as
+// are "inf" and "nan", which JSON cannot hold.
+func coerceValue(v string) interface{} {
+	if b, err := strconv.ParseBool(v); err == nil && len(v) > 1 {
+		return b
+	}
+
+	digits := strings.TrimPrefix(v, "-")
+	if len(digits) > 1 && digits[0] == '0' && digits[1] != '.' {
+		return v
+	}
+
+	if i, err := strconv.Atoi(v); err == nil {
+		return i
+	}
+
+	if f, err := strconv.ParseFloat(v, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+		return f
+	}
+
+	return v
+}
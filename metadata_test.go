@@ -0,0 +1,119 @@
+package traindown
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMetadataAccessors(t *testing.T) {
+	md := Metadata{
+		"bool":   "true",
+		"float":  "8.5",
+		"int":    "010",
+		"native": 3,
+		"whole":  2.0,
+		"word":   "heavy",
+	}
+
+	if b, ok := md.Bool("bool"); !ok || !b {
+		t.Errorf("Failed to read bool")
+	}
+
+	if _, ok := md.Bool("word"); ok {
+		t.Errorf("Read a word as a bool")
+	}
+
+	if f, ok := md.Float("float"); !ok || f != 8.5 {
+		t.Errorf("Failed to read float")
+	}
+
+	if f, ok := md.Float("native"); !ok || f != 3 {
+		t.Errorf("Failed to read native int as float")
+	}
+
+	if i, ok := md.Int("int"); !ok || i != 10 {
+		t.Errorf("Failed to read int with a leading zero")
+	}
+
+	if i, ok := md.Int("whole"); !ok || i != 2 {
+		t.Errorf("Failed to read whole float as int")
+	}
+
+	if _, ok := md.Int("float"); ok {
+		t.Errorf("Read a fractional value as an int")
+	}
+
+	if s, ok := md.String("native"); !ok || s != "3" {
+		t.Errorf("Failed to read native int as string")
+	}
+
+	if _, ok := md.String("missing"); ok {
+		t.Errorf("Read a missing key")
+	}
+
+	if _, ok := md.Float("missing"); ok {
+		t.Errorf("Read a missing key")
+	}
+
+	if l, ok := (Metadata{"list": " a, b ,, c "}).List("list"); !ok || len(l) != 3 || l[1] != "b" {
+		t.Errorf("Failed to read list: %v", l)
+	}
+}
+
+func TestCoerceValue(t *testing.T) {
+	cases := map[string]interface{}{
+		"true":    true,
+		"False":   false,
+		"1":       1,
+		"-3":      -3,
+		"0":       0,
+		"0.5":     0.5,
+		"8.5":     8.5,
+		"010":     "010",
+		"t":       "t",
+		"heavy":   "heavy",
+		"14:30":   "14:30",
+		"1e3":     1000.0,
+		"-0.25":   -0.25,
+		"-007":    "-007",
+		"3-1-2-0": "3-1-2-0",
+		"inf":     "inf",
+		"-Inf":    "-Inf",
+		"NaN":     "NaN",
+		"1e400":   "1e400",
+	}
+
+	for in, want := range cases {
+		if got := coerceValue(in); !reflect.DeepEqual(got, want) {
+			t.Errorf("Incorrect coercion of %q: %#v", in, got)
+		}
+	}
+}
+
+func TestSplitPairs(t *testing.T) {
+	cases := map[string][]string{
+		"rpe: 8, tempo: 3010":            {"rpe: 8", "tempo: 3010"},
+		"muscles: chest, triceps":        {"muscles: chest, triceps"},
+		"at: 10:00, 11:00":               {"at: 10:00, 11:00"},
+		"start: 14:30, end: 15:45":       {"start: 14:30", "end: 15:45"},
+		"url: https://x.com/a,b, rpe: 9": {"url: https://x.com/a,b", "rpe: 9"},
+		"key: value":                     {"key: value"},
+		"flag":                           {"flag"},
+		"a: 1,b: 2,c: 3":                 {"a: 1", "b: 2", "c: 3"},
+		"list: a, b, c, done: true":      {"list: a, b, c", "done: true"},
+	}
+
+	for in, want := range cases {
+		if got := splitPairs(in); !reflect.DeepEqual(got, want) {
+			t.Errorf("Incorrect split of %q: %q", in, got)
+		}
+	}
+}
+
+func TestNormalizeKey(t *testing.T) {
+	for _, k := range []string{"Body Weight", "body_weight", "body-weight", "  BODY   weight ", "body__weight"} {
+		if got := NormalizeKey(k); got != "body_weight" {
+			t.Errorf("Incorrect normalization of %q: %q", k, got)
+		}
+	}
+}
@@ -2,19 +2,25 @@ package traindown
 
 import (
 	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
 )
 
-// Movement is an thing you do, you know?
+// Movement is an thing you do, you know? Parsed movements leave Metadata nil
+// until metadata is given; see Metadata.Set.
 type Movement struct {
-	DefaultUnit string `json:"defaultUnit,omitempty"`
-	Name        string `json:"name"`
-	Sequence    int    `json:"sequence"`
-	SuperSet    bool   `json:"superSet"`
+	DefaultUnit string      `json:"defaultUnit,omitempty"`
+	Name        string      `json:"name"`
+	Sequence    int         `json:"sequence"`
+	Span        *SourceSpan `json:"span,omitempty"`
+	SuperSet    bool        `json:"superSet,omitempty"`
 
 	Performances []*Performance `json:"performances"`
 
-	Metadata Metadata `json:"metadata"`
-	Notes    []string `json:"notes"`
+	Metadata Metadata `json:"metadata,omitempty"`
+	Notes    []string `json:"notes,omitempty"`
 }
 
 /* Public */
@@ -33,6 +39,121 @@ func (m Movement) String() string {
 	return string(ms)
 }
 
+// MixedUnitsError is returned alongside a volume total that summed loads of
+// differing units.
+type MixedUnitsError struct {
+	Units []string
+}
+
+func (e *MixedUnitsError) Error() string {
+	return fmt.Sprintf("Volume mixes units: %s", strings.Join(e.Units, ", "))
+}
+
+func volumeTotal(v map[string]float32) (float32, error) {
+	var total float32
+	units := make([]string, 0, len(v))
+	for u, uv := range v {
+		total += uv
+		units = append(units, u)
+	}
+
+	if len(units) > 1 {
+		sort.Strings(units)
+		return total, &MixedUnitsError{units}
+	}
+
+	return total, nil
+}
+
+// Muscles lists the muscle groups given by the "muscles" metadata, as in
+// "# muscles: chest, triceps".
+func (m Movement) Muscles() []string {
+	k, ok := m.Metadata.lookup("muscles")
+	if !ok {
+		return nil
+	}
+	muscles, _ := m.Metadata.List(k)
+	return muscles
+}
+
+// NotesText joins the Movement notes with sep, skipping blank notes.
+func (m Movement) NotesText(sep string) string {
+	return joinNotes(m.Notes, sep)
+}
+
+// TopSet returns the Performance with the heaviest Load, preferring the
+// lowest Sequence on a tie. It returns nil for a Movement without
+// performances.
+func (m Movement) TopSet() *Performance {
+	var top *Performance
+	for _, p := range m.Performances {
+		if top == nil ||
+			p.Load > top.Load ||
+			(p.Load == top.Load && p.Sequence < top.Sequence) {
+			top = p
+		}
+	}
+	return top
+}
+
+// WorkingSets returns the performances whose Load is at least threshold
+// percent of the TopSet's Load, in their original order.
+func (m Movement) WorkingSets(threshold float32) []*Performance {
+	top := m.TopSet()
+	if top == nil {
+		return nil
+	}
+
+	min := top.Load * threshold / 100
+
+	var ws []*Performance
+	for _, p := range m.Performances {
+		if p.Load >= min {
+			ws = append(ws, p)
+		}
+	}
+	return ws
+}
+
+// WarmupThreshold is the percent of the TopSet's Load below which
+// ClassifySets considers a ramping set a warmup.
+const WarmupThreshold float32 = 70
+
+// ClassifySets splits the performances into warmups and working sets, each in
+// their original order, using ClassifySetsAt with WarmupThreshold.
+func (m *Movement) ClassifySets() (warmups, working []*Performance) {
+	return m.ClassifySetsAt(WarmupThreshold)
+}
+
+// ClassifySetsAt splits the performances into warmups and working sets. Only
+// the ramp counts as warming up: the performances before the first one whose
+// Load is at least threshold percent of the TopSet's Load are warmups, and
+// every performance from then on is a working set. So light back-off sets and
+// the way down a pyramid are working sets, and a descending scheme that
+// starts heavy has no warmups. A Movement without any load is all working.
+func (m *Movement) ClassifySetsAt(threshold float32) (warmups, working []*Performance) {
+	top := m.TopSet()
+	if top == nil {
+		return nil, nil
+	}
+
+	min := top.Load * threshold / 100
+
+	for i, p := range m.Performances {
+		if p.Load >= min {
+			return m.Performances[:i:i], m.Performances[i:]
+		}
+	}
+	return nil, m.Performances
+}
+
+// Volume computes the total volume of the Movement regardless of unit. If the
+// performances use more than one unit the total is still returned along with
+// a *MixedUnitsError; use Volumes for a per unit breakdown.
+func (m Movement) Volume() (float32, error) {
+	return volumeTotal(m.Volumes())
+}
+
 // Volumes computes the volume performed by unit.
 func (m Movement) Volumes() map[string]float32 {
 	v := make(map[string]float32)
@@ -50,8 +171,62 @@ func (m Movement) Volumes() map[string]float32 {
 	return v
 }
 
+// BestEstimatedOneRepMax is the highest EstimatedOneRepMax among the
+// Movement's performances.
+func (m Movement) BestEstimatedOneRepMax(formula string) float32 {
+	var best float32
+	for _, p := range m.Performances {
+		if e := p.EstimatedOneRepMax(formula); e > best {
+			best = e
+		}
+	}
+	return best
+}
+
+// ComputePercentages sets the PercentOfMax of each Performance relative to the
+// heaviest Load in the Movement. Percentages are rounded to two decimal places
+// and performances without a load are left at zero. Prescribed percentages
+// are left untouched.
+func (m *Movement) ComputePercentages() {
+	max := m.maxLoad()
+	for _, p := range m.Performances {
+		p.PercentOfMax = percentOfMax(p, max)
+	}
+}
+
 /* Private */
 
+func (m *Movement) maxLoad() float32 {
+	var max float32
+	for _, p := range m.Performances {
+		if p.Load > max {
+			max = p.Load
+		}
+	}
+	return max
+}
+
+// percentOfMax is the PercentOfMax that ComputePercentages gives p in a
+// Movement whose heaviest Load is max.
+func percentOfMax(p *Performance, max float32) float32 {
+	if p.PrescribedPercent {
+		return p.PercentOfMax
+	}
+	if p.Load == 0 || max == 0 {
+		return 0
+	}
+	return float32(math.Round(float64(p.Load/max)*10000) / 100)
+}
+
+// newMovement is NewMovement without the Metadata map, which the parser
+// allocates only once metadata is given.
+func newMovement() *Movement {
+	return &Movement{
+		Notes:        make([]string, 0),
+		Performances: make([]*Performance, 0),
+	}
+}
+
 func (m *Movement) assignSpecial(k string, v string) bool {
 	if isUnit(k) {
 		m.DefaultUnit = v
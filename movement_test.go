@@ -62,3 +62,146 @@ func TestAssignSpecialToMovement(t *testing.T) {
 		}
 	}
 }
+
+func TestComputePercentages(t *testing.T) {
+	m := NewMovement()
+	p1 := &Performance{Load: 100.0}
+	p2 := &Performance{Load: 300.0}
+	p3 := &Performance{Load: 0.0}
+	p4 := &Performance{Load: 200.0}
+	m.Performances = []*Performance{p1, p2, p3, p4}
+
+	m.ComputePercentages()
+
+	if p1.PercentOfMax != 33.33 ||
+		p2.PercentOfMax != 100 ||
+		p3.PercentOfMax != 0 ||
+		p4.PercentOfMax != 66.67 {
+		t.Errorf("Incorrect percentages: %v, %v, %v, %v", p1.PercentOfMax, p2.PercentOfMax, p3.PercentOfMax, p4.PercentOfMax)
+	}
+
+	empty := NewMovement()
+	empty.ComputePercentages()
+}
+
+func TestVolumeForMovement(t *testing.T) {
+	m := NewMovement()
+	m.Performances = []*Performance{
+		&Performance{Unit: "kg", Load: 100.0, Reps: 10, Sets: 1},
+		&Performance{Unit: "kg", Load: 50.0, Reps: 5, Sets: 2},
+	}
+
+	v, err := m.Volume()
+
+	if err != nil || v != 1500 {
+		t.Errorf("Expected 1500 without error, got %v (%v)", v, err)
+	}
+
+	m.Performances = append(m.Performances, &Performance{Unit: "lb", Load: 100.0, Reps: 1, Sets: 1})
+
+	v, err = m.Volume()
+
+	if v != 1600 {
+		t.Errorf("Expected 1600, got %v", v)
+	}
+
+	mixed, ok := err.(*MixedUnitsError)
+	if !ok || len(mixed.Units) != 2 || mixed.Units[0] != "kg" || mixed.Units[1] != "lb" {
+		t.Errorf("Expected a MixedUnitsError, got %v", err)
+	}
+}
+
+func TestBestEstimatedOneRepMax(t *testing.T) {
+	m := NewMovement()
+
+	if best := m.BestEstimatedOneRepMax(Epley); best != 0 {
+		t.Errorf("Expected 0 for empty movement, got %v", best)
+	}
+
+	m.Performances = []*Performance{
+		&Performance{Load: 300, Reps: 10},
+		&Performance{Load: 350, Reps: 1},
+		&Performance{Load: 0, Reps: 0},
+	}
+
+	if best := m.BestEstimatedOneRepMax(Epley); best != 400 {
+		t.Errorf("Expected 400, got %v", best)
+	}
+}
+
+func TestTopSetAndWorkingSets(t *testing.T) {
+	m := NewMovement()
+
+	if m.TopSet() != nil || m.WorkingSets(80) != nil {
+		t.Errorf("Expected nil for an empty movement")
+	}
+
+	p1 := &Performance{Load: 100, Sequence: 1}
+	p2 := &Performance{Load: 200, Sequence: 2}
+	p3 := &Performance{Load: 250, Sequence: 3}
+	p4 := &Performance{Load: 250, Sequence: 4}
+	p5 := &Performance{Load: 225, Sequence: 5}
+	m.Performances = []*Performance{p1, p2, p4, p3, p5}
+
+	if top := m.TopSet(); top != p3 {
+		t.Errorf("Incorrect top set: %v", top)
+	}
+
+	ws := m.WorkingSets(80)
+
+	if len(ws) != 4 || ws[0] != p2 || ws[1] != p4 || ws[2] != p3 || ws[3] != p5 {
+		t.Errorf("Incorrect working sets: %v", ws)
+	}
+
+	if ws = m.WorkingSets(95); len(ws) != 2 {
+		t.Errorf("Incorrect working sets: %v", ws)
+	}
+}
+
+func TestClassifySets(t *testing.T) {
+	session, err := ParseString(`
+    squat:
+      45 10r
+      135 5r
+      225 5r
+      315 3r
+      225 8r
+      135 10r
+
+    deadlift:
+      405 1r
+      315 3r
+      225 5r
+
+    pullup:
+      bw 10r
+      bw 8r`)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	warmups, working := session.Movements[0].ClassifySets()
+
+	if len(warmups) != 2 || warmups[1].Load != 135 || len(working) != 4 || working[3].Load != 135 {
+		t.Errorf("Unexpected squat sets: %v %v", warmups, working)
+	}
+
+	warmups, working = session.Movements[0].ClassifySetsAt(40)
+
+	if len(warmups) != 1 || len(working) != 5 {
+		t.Errorf("Unexpected squat sets at 40%%: %v %v", warmups, working)
+	}
+
+	if warmups, working = session.Movements[1].ClassifySets(); len(warmups) != 0 || len(working) != 3 {
+		t.Errorf("Expected a descending scheme without warmups: %v %v", warmups, working)
+	}
+
+	if warmups, working = session.Movements[2].ClassifySets(); len(warmups) != 0 || len(working) != 2 {
+		t.Errorf("Expected unloaded sets to be working: %v %v", warmups, working)
+	}
+
+	if warmups, working = NewMovement().ClassifySets(); warmups != nil || working != nil {
+		t.Errorf("Expected nothing for an empty movement: %v %v", warmups, working)
+	}
+}
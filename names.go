@@ -0,0 +1,37 @@
+package traindown
+
+import (
+	"strings"
+)
+
+// CanonicalizeNames renames Movements using aliases, a map from alias to
+// canonical name. Matching ignores case and surrounding whitespace, and each
+// canonical name is also an alias of itself, so with {"bench": "Bench Press"}
+// both "BENCH" and "bench press" become "Bench Press". Unknown names are left
+// untouched.
+func (s *Session) CanonicalizeNames(aliases map[string]string) {
+	lookup := canonicalLookup(aliases)
+
+	for _, m := range s.Movements {
+		if c, ok := lookup[nameKey(m.Name)]; ok {
+			m.Name = c
+		}
+	}
+}
+
+/* Private */
+
+func canonicalLookup(aliases map[string]string) map[string]string {
+	lookup := make(map[string]string, len(aliases)*2)
+	for _, c := range aliases {
+		lookup[nameKey(c)] = c
+	}
+	for a, c := range aliases {
+		lookup[nameKey(a)] = c
+	}
+	return lookup
+}
+
+func nameKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -0,0 +1,26 @@
+package traindown
+
+import (
+	"testing"
+)
+
+func TestCanonicalizeNames(t *testing.T) {
+	s := NewSession()
+	for _, n := range []string{"Bench Press", "bench press ", "Bench", "BP", "squat", "Deadlift"} {
+		s.Movements = append(s.Movements, &Movement{Name: n})
+	}
+
+	s.CanonicalizeNames(map[string]string{
+		"bench": "Bench Press",
+		"bp":    "Bench Press",
+		"Squat": "Back Squat",
+	})
+
+	expected := []string{"Bench Press", "Bench Press", "Bench Press", "Bench Press", "Back Squat", "Deadlift"}
+
+	for i, n := range expected {
+		if s.Movements[i].Name != n {
+			t.Errorf("Expected %q, got %q", n, s.Movements[i].Name)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package traindown
+
+import (
+	"context"
+	"time"
+)
+
+// Option configures how a Traindown document is parsed.
+type Option func(*parseConfig)
+
+type parseConfig struct {
+	dateLayout  string
+	defaultDate time.Time
+	defaultUnit string
+	percentages bool
+	strict      bool
+
+	duplicateKeys     bool
+	loadPrecision     int
+	roundLoads        bool
+	noUnitInheritance bool
+	numericGuards     bool
+	spans             bool
+	typedMetadata     bool
+	unknownTokens     bool
+	validatePercents  bool
+
+	keyNormalizer func(string) string
+
+	// ctx, when set, is checked while parsing; see ParseStringContext.
+	ctx context.Context
+}
+
+// WithDateLayout parses dates with layout, as understood by time.Parse, before
+// falling back to the usual guessing. Use it to pin down ambiguous dates such
+// as "02/01/2006". Sessions record the layout in DateLayout when it was used.
+func WithDateLayout(layout string) Option {
+	return func(c *parseConfig) {
+		c.dateLayout = layout
+	}
+}
+
+// WithDefaultDate dates sessions that have no date line, or whose date fails
+// to parse, with d.
+func WithDefaultDate(d time.Time) Option {
+	return func(c *parseConfig) {
+		c.defaultDate = d
+	}
+}
+
+// WithDefaultUnit sets the unit inherited by performances when neither the
+// session nor the movement declares one.
+func WithDefaultUnit(u string) Option {
+	return func(c *parseConfig) {
+		c.defaultUnit = u
+	}
+}
+
+// WithNumericGuards toggles recording an error for each negative load, reps,
+// or sets and each load over MaxLoad once a session has been parsed. The
+// values themselves are kept as written.
+func WithNumericGuards(enabled bool) Option {
+	return func(c *parseConfig) {
+		c.numericGuards = enabled
+	}
+}
+
+// WithPercentages toggles computing PercentOfMax for every movement once it
+// has been parsed.
+func WithPercentages(enabled bool) Option {
+	return func(c *parseConfig) {
+		c.percentages = enabled
+	}
+}
+
+// WithDuplicateKeyWarnings toggles recording a warning when a metadata key is
+// given more than once in the same scope. The last value given is kept either
+// way.
+func WithDuplicateKeyWarnings(enabled bool) Option {
+	return func(c *parseConfig) {
+		c.duplicateKeys = enabled
+	}
+}
+
+// WithLoadPrecision rounds every Load to decimals places once a session has
+// been parsed; see Session.RoundLoads.
+func WithLoadPrecision(decimals int) Option {
+	return func(c *parseConfig) {
+		c.loadPrecision = decimals
+		c.roundLoads = true
+	}
+}
+
+// WithMetadataKeyNormalizer rewrites every metadata key with normalize before
+// it is stored. Special keys such as unit and rpe are recognized before
+// normalizing, and the raw keys remain available from Tokenize. See
+// NormalizeKey for a ready made normalizer.
+func WithMetadataKeyNormalizer(normalize func(string) string) Option {
+	return func(c *parseConfig) {
+		c.keyNormalizer = normalize
+	}
+}
+
+// WithPercentValidation toggles recording a warning for prescribed loads over
+// 100%.
+func WithPercentValidation(enabled bool) Option {
+	return func(c *parseConfig) {
+		c.validatePercents = enabled
+	}
+}
+
+// WithSourceSpans toggles recording where each Movement and Performance was
+// found in the source.
+func WithSourceSpans(enabled bool) Option {
+	return func(c *parseConfig) {
+		c.spans = enabled
+	}
+}
+
+// WithStrict toggles returning a ParseErrors error when any errors were
+// recorded on a Session.
+func WithStrict(enabled bool) Option {
+	return func(c *parseConfig) {
+		c.strict = enabled
+	}
+}
+
+// WithUnknownTokenError toggles recording an error for each token the parser
+// does not know how to handle, rather than skipping it, to catch syntax newer
+// than this version of the library.
+func WithUnknownTokenError(enabled bool) Option {
+	return func(c *parseConfig) {
+		c.unknownTokens = enabled
+	}
+}
+
+// WithUnitInheritance toggles filling in the unit of performances that do not
+// state one from their movement or session. It is enabled by default.
+func WithUnitInheritance(enabled bool) Option {
+	return func(c *parseConfig) {
+		c.noUnitInheritance = !enabled
+	}
+}
+
+// WithTypedMetadata toggles storing metadata values that are clearly bools,
+// ints, or float64s as those types rather than as strings. Numbers with a
+// leading zero, such as "010", are ambiguous and stay strings.
+func WithTypedMetadata(enabled bool) Option {
+	return func(c *parseConfig) {
+		c.typedMetadata = enabled
+	}
+}
+
+/* Private */
+
+func newParseConfig(opts []Option) parseConfig {
+	var c parseConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+func (c parseConfig) newSession() *Session {
+	s := NewSession()
+	s.DefaultUnit = c.defaultUnit
+	return s
+}
+
+func withContext(ctx context.Context) Option {
+	return func(c *parseConfig) {
+		c.ctx = ctx
+	}
+}
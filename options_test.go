@@ -0,0 +1,403 @@
+package traindown
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timtadh/lexmachine"
+)
+
+func TestParseWithoutOptions(t *testing.T) {
+	session, err := ParseStringWithOptions("movement:\n100\n200")
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	p := session.Movements[0].Performances[0]
+
+	if !session.Date.IsZero() || p.Unit != "unknown unit" || p.PercentOfMax != 0 {
+		t.Errorf("Unexpected defaults: %v", session)
+	}
+}
+
+func TestWithDefaultDate(t *testing.T) {
+	d := time.Date(2020, 2, 2, 0, 0, 0, 0, time.UTC)
+
+	session, err := ParseStringWithOptions("movement:\n100", WithDefaultDate(d))
+
+	if err != nil || session.Date != d {
+		t.Errorf("Failed to apply default date: %v (%v)", session.Date, err)
+	}
+
+	session, err = ParseStringWithOptions("@ not a date\nmovement:\n100", WithDefaultDate(d))
+
+	if err != nil || session.Date != d || len(session.Errors) != 1 {
+		t.Errorf("Failed to fall back to default date: %v (%v)", session.Date, err)
+	}
+
+	session, err = ParseStringWithOptions("@ 2020-01-01\nmovement:\n100", WithDefaultDate(d))
+
+	if err != nil || session.Date != time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC) {
+		t.Errorf("Default date overrode the date line: %v (%v)", session.Date, err)
+	}
+}
+
+func TestWithDefaultDateFromFilename(t *testing.T) {
+	dir, err := ioutil.TempDir("", "traindown")
+
+	if err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "2021-03-04.traindown")
+	if err := ioutil.WriteFile(path, []byte("# time: 6:30am\nsquat:\n  225 5r\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	d, err := time.Parse("2006-01-02", strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+
+	if err != nil {
+		t.Fatalf("Failed to read date from filename: %v", err)
+	}
+
+	session, err := ParseFile(path, WithDefaultDate(d))
+
+	if err != nil {
+		t.Fatalf("Failed to parse file: %v", err)
+	}
+
+	if !session.Date.Equal(d) || len(session.Movements) != 1 || len(session.Errors) != 0 {
+		t.Errorf("Failed to date the file from its name: %v", session)
+	}
+
+	sessions, err := ParseSessions("squat:\n  225 5r\n@ 2021-03-05\nbench:\n  135 5r\n", WithDefaultDate(d))
+
+	if err != nil || len(sessions) != 2 {
+		t.Fatalf("Failed to parse sessions: %v (%v)", sessions, err)
+	}
+
+	if !sessions[0].Date.Equal(d) || !sessions[1].Date.Equal(time.Date(2021, 3, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected dates: %v, %v", sessions[0].Date, sessions[1].Date)
+	}
+}
+
+func TestWithDefaultUnit(t *testing.T) {
+	text := `
+    squat:
+      100
+      200 kg
+    bench:
+      # unit: lb
+      100`
+
+	session, err := ParseStringWithOptions(text, WithDefaultUnit("kg"))
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	squat := session.Movements[0].Performances
+	bench := session.Movements[1].Performances
+
+	if squat[0].Unit != "kg" || squat[1].Unit != "kg" || bench[0].Unit != "lb" {
+		t.Errorf("Failed to apply default unit: %v %v", squat, bench)
+	}
+}
+
+func TestWithPercentages(t *testing.T) {
+	session, err := ParseStringWithOptions("movement:\n100\n200", WithPercentages(true))
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	ps := session.Movements[0].Performances
+
+	if ps[0].PercentOfMax != 50 || ps[1].PercentOfMax != 100 {
+		t.Errorf("Failed to compute percentages: %v", ps)
+	}
+}
+
+func TestWithStrict(t *testing.T) {
+	text := "@ not a date\nmovement:\n100"
+
+	session, err := ParseStringWithOptions(text, WithStrict(true))
+
+	if _, ok := err.(ParseErrors); !ok || len(session.Movements) != 1 {
+		t.Errorf("Expected ParseErrors with the session, got %v (%v)", err, session)
+	}
+
+	if _, err = ParseStringWithOptions(text, WithStrict(false)); err != nil {
+		t.Errorf("Expected lenient parsing, got %v", err)
+	}
+
+	sessions, err := ParseSessions("@ 2020-01-01\nm:\n1\n@ nope\nm:\n1", WithStrict(true))
+
+	if errs, ok := err.(ParseErrors); !ok || len(errs) != 1 || len(sessions) != 2 {
+		t.Errorf("Expected ParseErrors with the sessions, got %v (%v)", err, sessions)
+	}
+}
+
+func TestWithTypedMetadata(t *testing.T) {
+	text := `
+    # bodyweight: 80.5
+    # pr: true
+    # zip: 02134
+    movement:
+      # attempts: 3
+      100`
+
+	session, err := ParseStringWithOptions(text, WithTypedMetadata(true))
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if session.Metadata["bodyweight"] != 80.5 ||
+		session.Metadata["pr"] != true ||
+		session.Metadata["zip"] != "02134" ||
+		session.Movements[0].Metadata["attempts"] != 3 {
+		t.Errorf("Failed to coerce metadata: %#v %#v", session.Metadata, session.Movements[0].Metadata)
+	}
+
+	session, err = ParseString(text)
+
+	if err != nil || session.Metadata["bodyweight"] != "80.5" {
+		t.Errorf("Coerced metadata without the option: %#v", session.Metadata)
+	}
+}
+
+func TestWithUnitInheritance(t *testing.T) {
+	text := `
+    # unit: lb
+    squat:
+      225
+      100 kg
+        # unit: kg
+    bench:
+      135
+      60 kg`
+
+	session, err := ParseString(text)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	units := []string{}
+	for _, m := range session.Movements {
+		for _, p := range m.Performances {
+			units = append(units, p.Unit)
+		}
+	}
+
+	if fmt.Sprint(units) != "[lb kg lb kg]" {
+		t.Errorf("Incorrect inherited units: %v", units)
+	}
+
+	session, err = ParseStringWithOptions(text, WithUnitInheritance(false))
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	squat := session.Movements[0].Performances
+	if squat[0].Unit != "unknown unit" || squat[1].Unit != "kg" || session.DefaultUnit != "lb" {
+		t.Errorf("Inherited units with inheritance disabled: %v", squat)
+	}
+}
+
+func TestWithMetadataKeyNormalizer(t *testing.T) {
+	text := `
+    # Body Weight: 80
+    # Unit: kg
+    Squat:
+      # Bar-Type: ss yoke
+      100
+        # RPE: 8`
+
+	session, err := ParseStringWithOptions(text, WithMetadataKeyNormalizer(NormalizeKey))
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	m := session.Movements[0]
+	p := m.Performances[0]
+
+	if session.Metadata["body_weight"] != "80" ||
+		session.DefaultUnit != "kg" ||
+		m.Metadata["bar_type"] != "ss yoke" ||
+		p.Metadata["rpe"] != "8" ||
+		p.RPE != 8 {
+		t.Errorf("Failed to normalize keys: %v", session)
+	}
+
+	session, err = ParseString(text)
+
+	if err != nil || session.Metadata["Body Weight"] != "80" {
+		t.Errorf("Normalized keys without the option: %v", session.Metadata)
+	}
+}
+
+func TestWithDateLayout(t *testing.T) {
+	text := "@ 02/01/2020\nsquat:\n  100"
+
+	session, err := ParseString(text)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if session.Date != time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC) || session.DateLayout != "" {
+		t.Errorf("Expected a guessed month first date, got %v (%q)", session.Date, session.DateLayout)
+	}
+
+	session, err = ParseStringWithOptions(text, WithDateLayout("02/01/2006"))
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if session.Date != time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC) || session.DateLayout != "02/01/2006" {
+		t.Errorf("Expected a day first date, got %v (%q)", session.Date, session.DateLayout)
+	}
+
+	session, err = ParseStringWithOptions("@ 2020-01-03\nsquat:\n  100", WithDateLayout("02/01/2006"))
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if session.Date != time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC) || session.DateLayout != "" {
+		t.Errorf("Expected a fallback date, got %v (%q)", session.Date, session.DateLayout)
+	}
+}
+
+func TestWithDuplicateKeyWarnings(t *testing.T) {
+	text := `
+    # bodyweight: 80
+    # bodyweight: 81
+    squat:
+      # bar: ss
+      100
+        # rpe: 8
+        # rpe: 9
+      100
+        # rpe: 9`
+
+	session, err := ParseString(text)
+
+	if err != nil || len(session.Errors) != 0 {
+		t.Fatalf("Expected no warnings by default: %v %v", err, session.Errors)
+	}
+
+	session, err = ParseStringWithOptions(text, WithDuplicateKeyWarnings(true))
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if len(session.Errors) != 2 {
+		t.Fatalf("Expected 2 warnings, got %v", session.Errors)
+	}
+
+	if session.Metadata["bodyweight"] != "81" || session.Movements[0].Performances[0].Metadata["rpe"] != "9" {
+		t.Errorf("Expected the last value to win: %v", session)
+	}
+
+	for i, line := range []int{3, 8} {
+		if pe, ok := session.Errors[i].(*ParseError); !ok || pe.Line != line {
+			t.Errorf("Expected a warning on line %d, got %v", line, session.Errors[i])
+		}
+	}
+}
+
+func TestWithLoadPrecision(t *testing.T) {
+	text := "squat:\n  102.46\n  100.444"
+
+	session, err := ParseString(text)
+
+	if err != nil || session.Movements[0].Performances[0].Load != 102.46 {
+		t.Fatalf("Expected loads untouched by default: %v %v", err, session)
+	}
+
+	session, err = ParseStringWithOptions(text, WithLoadPrecision(1))
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	ps := session.Movements[0].Performances
+
+	if ps[0].Load != 102.5 || ps[1].Load != 100.4 {
+		t.Errorf("Failed to round loads: %v %v", ps[0].Load, ps[1].Load)
+	}
+}
+
+func TestWithNumericGuards(t *testing.T) {
+	text := "squat:\n  20000 5r\n  1000000000000000000000000000000000000000000 5r\n  100 5r"
+
+	session, err := ParseString(text)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	// The overflowing load fails to parse with or without guards.
+	if len(session.Errors) != 1 || session.Movements[0].Performances[0].Load != 20000 {
+		t.Errorf("Expected only the overflow error by default: %v", session.Errors)
+	}
+
+	session, err = ParseStringWithOptions(text, WithNumericGuards(true))
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if len(session.Errors) != 2 || !strings.Contains(session.Errors[1].Error(), "squat #1: Absurd load") {
+		t.Errorf("Expected an absurd load error: %v", session.Errors)
+	}
+
+	if session.Movements[0].Performances[0].Load != 20000 {
+		t.Errorf("Expected the raw load to be kept: %v", session.Movements[0])
+	}
+}
+
+func TestWithUnknownTokenError(t *testing.T) {
+	tokens, err := Tokenize("squat:\n  100 5r // heavy")
+
+	if err != nil {
+		t.Fatalf("Failed to tokenize: %q", err)
+	}
+
+	unknown := &Token{&lexmachine.Token{Type: len(Tokens), Value: "~", StartLine: 2, StartColumn: 9}}
+	tokens = append(tokens, unknown)
+
+	sessions, err := parseTokens(tokens, false, newParseConfig(nil))
+
+	if err != nil || len(sessions[0].Errors) != 0 {
+		t.Errorf("Expected unknown tokens to be skipped: %v %v", sessions[0].Errors, err)
+	}
+
+	sessions, err = parseTokens(tokens, false, newParseConfig([]Option{WithUnknownTokenError(true)}))
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if errs := sessions[0].Errors; len(errs) != 1 || errs[0].Error() != fmt.Sprintf(`2:9: Unknown token #%d: "~"`, len(Tokens)) {
+		t.Errorf("Expected an unknown token error: %v", errs)
+	}
+
+	if p := sessions[0].Movements[0].Performances[0]; p.Load != 100 || p.Reps != 5 {
+		t.Errorf("Unexpected performance: %v", p)
+	}
+}
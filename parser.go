@@ -1,7 +1,14 @@
 package traindown
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -9,11 +16,19 @@ import (
 	"github.com/araddon/dateparse"
 )
 
+// ErrEmptyInput is returned, along with an empty Session, when the input holds
+// nothing but whitespace and comments.
+var ErrEmptyInput = errors.New("Empty input")
+
 // ParseByte takes in a Traindown byte slice and returns a pointer to a Session.
+// Like every Parse function it is safe for concurrent use.
 func ParseByte(txt []byte) (*Session, error) {
-	s, err := parse("", txt)
+	s, err := parse("", txt, nil)
 
 	if err != nil {
+		if err == ErrEmptyInput {
+			return s, err
+		}
 		return &Session{}, err
 	}
 
@@ -22,15 +37,120 @@ func ParseByte(txt []byte) (*Session, error) {
 
 // ParseString takes in a Traindown string and returns a pointer to a Session.
 func ParseString(txt string) (*Session, error) {
-	s, err := parse(txt, []byte(""))
+	return ParseStringWithOptions(txt)
+}
+
+// ParseStringWithOptions takes in a Traindown string and returns a pointer to
+// a Session, configured by opts.
+func ParseStringWithOptions(txt string, opts ...Option) (*Session, error) {
+	s, err := parse(txt, []byte(""), opts)
 
 	if err != nil {
+		if _, ok := err.(ParseErrors); ok || err == ErrEmptyInput {
+			return s, err
+		}
+		return &Session{}, err
+	}
+
+	return s, nil
+}
+
+// ParseStringContext is ParseStringWithOptions bounded by ctx. The context is
+// checked before lexing and every ctxCheckInterval tokens while parsing, and
+// its error is returned as soon as it is done.
+func ParseStringContext(ctx context.Context, txt string, opts ...Option) (*Session, error) {
+	if err := ctx.Err(); err != nil {
 		return &Session{}, err
 	}
 
+	return ParseStringWithOptions(txt, append(opts, withContext(ctx))...)
+}
+
+// ParseStringStrict behaves like ParseString but returns a ParseErrors error
+// when any errors were recorded on the Session while parsing.
+func ParseStringStrict(txt string) (*Session, error) {
+	return ParseStringWithOptions(txt, WithStrict(true))
+}
+
+// ParseErrors aggregates the errors recorded while parsing.
+type ParseErrors []error
+
+func (e ParseErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d parse error(s): %s", len(e), strings.Join(msgs, "; "))
+}
+
+// ParseError is an error recorded at a position in the source. A Warning is
+// recoverable: the input was understood, but is suspect or was substituted,
+// such as an unparsable date replaced by the default.
+type ParseError struct {
+	Line    int
+	Col     int
+	Msg     string
+	Warning bool
+}
+
+func (e ParseError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Col, e.Msg)
+}
+
+// ParseSessions takes in a Traindown string containing any number of dated
+// entries and returns a Session for each of them. A new Session begins
+// whenever a date line follows movements. A leading block without a date is
+// dated today (UTC) unless a default date is given. Empty input yields no
+// sessions and no error.
+func ParseSessions(txt string, opts ...Option) ([]*Session, error) {
+	return parseSessions(txt, []byte(""), true, opts)
+}
+
+// ParseFile reads the Traindown document at path and returns a pointer to a
+// Session.
+func ParseFile(path string, opts ...Option) (*Session, error) {
+	f, err := os.Open(path)
+
+	if err != nil {
+		return &Session{}, fmt.Errorf("Failed to open %q: %w", path, err)
+	}
+	defer f.Close()
+
+	s, err := ParseReader(f, opts...)
+
+	if err != nil {
+		return s, fmt.Errorf("Failed to parse %q: %w", path, err)
+	}
+
 	return s, nil
 }
 
+// ParseReader reads a Traindown document from r and returns a pointer to a
+// Session.
+func ParseReader(r io.Reader, opts ...Option) (*Session, error) {
+	b, err := ioutil.ReadAll(bufio.NewReader(r))
+
+	if err != nil {
+		return &Session{}, fmt.Errorf("Failed to read: %q", err)
+	}
+
+	s, err := parse("", b, opts)
+
+	if err != nil {
+		if _, ok := err.(ParseErrors); ok || err == ErrEmptyInput {
+			return s, err
+		}
+		return &Session{}, err
+	}
+
+	return s, nil
+}
+
+// today returns midnight of the current day in UTC.
+func today() time.Time {
+	return time.Now().UTC().Truncate(24 * time.Hour)
+}
+
 func floatValue(s string, t string) (float32, error) {
 	f, err := strconv.ParseFloat(s, 32)
 
@@ -41,158 +161,833 @@ func floatValue(s string, t string) (float32, error) {
 	return float32(f), nil
 }
 
-func intValue(s string, t string) (int, error) {
-	i, err := strconv.Atoi(s)
+// durationValue reads Go style durations such as "90s" or "1m30s", treating
+// a bare number as seconds. ISO 8601 durations such as "PT1M30S" are also
+// accepted; see isoDuration.
+func durationValue(s string, t string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
 
-	if err != nil {
+	if len(s) > 0 && (s[0] == 'P' || s[0] == 'p') {
+		d, ok := isoDuration(s)
+		if !ok {
+			return 0, fmt.Errorf("Failed to parse %q: %q", t, s)
+		}
+		return d, nil
+	}
+
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		if f < 0 {
+			return 0, fmt.Errorf("Failed to parse %q: %q", t, s)
+		}
+		return time.Duration(f * float64(time.Second)), nil
+	}
+
+	d, err := time.ParseDuration(s)
+
+	if err != nil || d < 0 {
 		return 0, fmt.Errorf("Failed to parse %q: %q", t, s)
 	}
 
-	return i, nil
+	return d, nil
 }
 
-func parse(str string, b []byte) (*Session, error) {
-	s := NewSession()
+// isoDuration reads an ISO 8601 duration of weeks, days, hours, minutes, and
+// seconds, such as "PT1M30S" or "P1DT2H". Years and months have no fixed
+// length and are rejected. Only the last number may be fractional.
+func isoDuration(s string) (time.Duration, bool) {
+	s = strings.ToUpper(s)
+	if len(s) < 3 || s[0] != 'P' {
+		return 0, false
+	}
 
-	lexer, err := NewLexer()
+	units := map[byte]time.Duration{'W': 7 * 24 * time.Hour, 'D': 24 * time.Hour}
+	order := "WD"
+	var d time.Duration
+	var fractional, timed bool
+
+	for i := 1; i < len(s); {
+		if s[i] == 'T' {
+			if timed || i == len(s)-1 {
+				return 0, false
+			}
+			units = map[byte]time.Duration{'H': time.Hour, 'M': time.Minute, 'S': time.Second}
+			order = "HMS"
+			timed = true
+			i++
+			continue
+		}
+
+		j := i
+		for j < len(s) && (s[j] == '.' || s[j] >= '0' && s[j] <= '9') {
+			j++
+		}
+		if j == i || j == len(s) || fractional {
+			return 0, false
+		}
+
+		unit, ok := units[s[j]]
+		pos := strings.IndexByte(order, s[j])
+		if !ok || pos < 0 {
+			return 0, false
+		}
+
+		n, err := strconv.ParseFloat(s[i:j], 64)
+		if err != nil {
+			return 0, false
+		}
+
+		fractional = strings.Contains(s[i:j], ".")
+		d += time.Duration(n * float64(unit))
+		order = order[pos+1:]
+		i = j + 1
+	}
+
+	return d, true
+}
+
+// loadRange reads a load or a load range such as "225-245". A single load is
+// a range of one.
+func loadRange(s string) (float32, float32, error) {
+	parts := strings.SplitN(s, "-", 2)
+
+	min, err := floatValue(parts[0], "load")
+
+	if err != nil || len(parts) == 1 {
+		return min, min, err
+	}
+
+	max, err := floatValue(parts[1], "load")
 
 	if err != nil {
-		return s, err
+		return min, min, err
 	}
 
+	if max < min {
+		return min, min, fmt.Errorf("Inverted load range: %q", s)
+	}
+
+	return min, max, nil
+}
+
+// splitAlias splits the "bp=Bench Press" value of an ALIAS token.
+func splitAlias(v string) (string, string) {
+	i := strings.IndexByte(v, '=')
+	return v[:i], v[i+1:]
+}
+
+// perSetReps reads a comma separated list of reps such as "5,5,3".
+func perSetReps(s string) ([]int, error) {
+	parts := strings.Split(s, ",")
+	reps := make([]int, len(parts))
+
+	for i, part := range parts {
+		r, err := strconv.Atoi(part)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse %q: %q", "per set reps", s)
+		}
+
+		reps[i] = r
+	}
+
+	return reps, nil
+}
+
+// intValue also accepts integral decimals such as "5.0", which some tools
+// export for reps and sets.
+func intValue(s string, t string) (int, error) {
+	i, err := strconv.Atoi(s)
+
+	if err == nil {
+		return i, nil
+	}
+
+	f, ferr := strconv.ParseFloat(s, 64)
+
+	if ferr != nil || f != math.Trunc(f) || math.Abs(f) > math.MaxInt32 {
+		return 0, fmt.Errorf("Failed to parse %q: %q", t, s)
+	}
+
+	return int(f), nil
+}
+
+func parse(str string, b []byte, opts []Option) (*Session, error) {
+	sessions, err := parseSessions(str, b, false, opts)
+
+	if len(sessions) == 0 {
+		return NewSession(), err
+	}
+
+	return sessions[0], err
+}
+
+func parseSessions(str string, b []byte, split bool, opts []Option) ([]*Session, error) {
 	var tokens []*Token
+	var err error
 	if str != "" {
-		tokens, err = lexer.Scan([]byte(str))
+		tokens, err = scan([]byte(str))
 	} else {
-		tokens, err = lexer.Scan(b)
+		tokens, err = scan(b)
 	}
 
 	if err != nil {
-		return s, err
+		return nil, err
 	}
 
-	m := NewMovement()
-	p := NewPerformance()
-	inSession := true
-	inPerformance := false
-	mSeq := 0
-	pSeq := 0
+	if isEmpty(tokens) {
+		if split {
+			return []*Session{}, nil
+		}
+		return nil, ErrEmptyInput
+	}
+
+	return parseTokens(tokens, split, newParseConfig(opts))
+}
 
+// isEmpty reports whether tokens hold nothing the parser would act on.
+func isEmpty(tokens []*Token) bool {
 	for _, tok := range tokens {
-		switch tok.Name() {
-		case "DATE":
-			d, err := dateparse.ParseAny(tok.Value())
+		if tok.Name() != "COMMENT" && tok.Name() != "ALIAS" {
+			return false
+		}
+	}
+	return true
+}
 
-			if err != nil {
-				s.Errors = append(s.Errors, fmt.Errorf("Failed to parse date: %q. Using today UTC", err))
-				s.Date = time.Now()
+// ctxCheckInterval is how many tokens are parsed between checks of the
+// context given to ParseStringContext.
+const ctxCheckInterval = 1024
+
+func parseTokens(tokens []*Token, split bool, cfg parseConfig) ([]*Session, error) {
+	return parseAliasedTokens(tokens, split, cfg, nil)
+}
+
+// parseAliasedTokens is parseTokens starting from the aliases given, which
+// gain those defined by the tokens, so they carry over to the next call.
+func parseAliasedTokens(tokens []*Token, split bool, cfg parseConfig, aliases map[string]string) ([]*Session, error) {
+	ps := newParser(split, cfg)
+	ps.aliases = aliases
+	ps.collectAliases(tokens)
+
+	for i, tok := range tokens {
+		if cfg.ctx != nil && i%ctxCheckInterval == 0 {
+			if err := cfg.ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
+		ps.handle(tok)
+	}
+
+	sessions := ps.finish()
+
+	if ps.cfg.strict {
+		var errs ParseErrors
+		for _, s := range sessions {
+			errs = append(errs, s.Errors...)
+		}
+
+		if len(errs) > 0 {
+			return sessions, errs
+		}
+	}
+
+	return sessions, nil
+}
+
+// parser holds the state of a single pass over a token stream.
+type parser struct {
+	cfg      parseConfig
+	split    bool
+	sessions []*Session
+
+	s *Session
+	m *Movement
+	p *Performance
+	// prev is the last Performance of the current Movement, for "same".
+	prev *Performance
+	// set is the Set being read, if the performance has set markers.
+	set *Set
+	// components are the tokens of the LoadComponents of the Performance,
+	// for errors once they are summed.
+	components []*Token
+
+	// aliases maps the key of each alias defined anywhere in the input to
+	// the movement name it expands to.
+	aliases map[string]string
+
+	// h receives elements in place of building sessions when streaming, and
+	// herr holds the first error it returned.
+	h    Handler
+	herr error
+
+	dated         bool
+	inSession     bool
+	inPerformance bool
+	repped        bool
+	mSeq          int
+	pSeq          int
+}
+
+func newParser(split bool, cfg parseConfig) *parser {
+	return &parser{
+		cfg:       cfg,
+		split:     split,
+		s:         cfg.newSession(),
+		m:         newMovement(),
+		p:         newPerformance(),
+		inSession: true,
+	}
+}
+
+func (ps *parser) handle(tok *Token) {
+	s := ps.s
+
+	if ps.cfg.spans && tok.Name() != "DATE" {
+		defer ps.extendSpans(tok)
+	}
+
+	switch tok.Name() {
+	case "FAILS", "LOAD", "MODIFIER", "REPS", "SCHEME", "SETS":
+		if ps.inSession {
+			ps.addError(tok, fmt.Errorf("Performance before any movement: %q", tok.Value()))
+			return
+		}
+	}
+
+	switch tok.Name() {
+	case "ALIAS":
+		alias, name := splitAlias(tok.Value())
+		if name == "" {
+			ps.addError(tok, fmt.Errorf("Alias without a name: %q", alias))
+		} else if ps.aliases[nameKey(alias)] != name {
+			ps.addWarning(tok, fmt.Errorf("Alias redefined, keeping the first: %q", alias))
+		}
+	case "DATE":
+		if ps.split && (ps.m.Name != "" || len(s.Movements) > 0) {
+			ps.endSession()
+			s = ps.s
+		}
+
+		d, err := ps.parseDate(s, tok.Value())
+
+		if err != nil {
+			if ps.cfg.defaultDate.IsZero() {
+				ps.addWarning(tok, fmt.Errorf("Failed to parse date: %q. Using today UTC", err))
+				s.Date = today()
 			} else {
-				s.Date = d
+				ps.addWarning(tok, fmt.Errorf("Failed to parse date: %q. Using default date", err))
+				s.Date = ps.cfg.defaultDate
 			}
-		case "FAILS":
-			i, err := intValue(tok.Value(), "fails")
+		} else {
+			s.Date = d
+		}
+		ps.dated = true
+	case "FAILS":
+		ps.beginUnloaded()
+		i, err := intValue(tok.Value(), "fails")
 
-			if err != nil {
-				s.Errors = append(s.Errors, err)
+		if err != nil {
+			ps.addError(tok, err)
+		}
+
+		if ps.set != nil {
+			ps.set.Fails = i
+		} else {
+			ps.p.Fails = i
+		}
+	case "LOAD":
+		if ps.inPerformance {
+			ps.flushPerformance()
+		}
+		// The lexer separates any unit from the load with a single space.
+		load, unit := tok.Value(), ""
+		if k := strings.IndexByte(load, ' '); k >= 0 {
+			load, unit = load[:k], load[k+1:]
+		}
+
+		if load == "same" {
+			ps.inPerformance = true
+			if ps.prev == nil {
+				ps.addError(tok, fmt.Errorf("Same load without a previous performance"))
+				return
 			}
+			ps.p.copyLoad(ps.prev)
+			return
+		}
 
-			p.Fails = i
-		case "LOAD":
-			if inPerformance {
-				p.Sequence = pSeq
-				p.maybeInheritUnit(s, m)
-				m.Performances = append(m.Performances, p)
-				p = NewPerformance()
-				pSeq++
+		if strings.Contains(load, "BW") {
+			// "BW +10bands" is bodyweight with a band rather than a unit.
+			if i := strings.Index(load, "BW"); unit != "" && i+2 < len(load) {
+				if lm, err := ParseLoadModifier(load[i+2:] + unit); err == nil {
+					load, unit = load[:i+2], ""
+					ps.p.LoadModifiers = append(ps.p.LoadModifiers, lm)
+				}
 			}
-			f, err := floatValue(tok.Value(), "load")
 
-			if err != nil {
-				s.Errors = append(s.Errors, err)
+			if _, _, err := relativeLoad(load); err != nil {
+				ps.addError(tok, err)
 			}
 
-			p.Load = f
-			inPerformance = true
-		case "METADATA":
-			pair := strings.Split(tok.Value(), ":")
-			key := strings.Trim(pair[0], " ")
-			value := strings.Trim(pair[1], " ")
+			ps.p.RelativeLoad = load
 
-			if inSession {
-				if !s.assignSpecial(key, value) {
-					s.Metadata[key] = value
-				}
-			} else if inPerformance {
-				if !p.assignSpecial(key, value) {
-					p.Metadata[key] = value
-				}
-			} else {
-				if !m.assignSpecial(key, value) {
-					m.Metadata[key] = value
-				}
+			if unit != "" {
+				ps.p.Unit = unit
 			}
-		case "MOVEMENT", "MOVEMENT_SS":
-			inSession = false
-
-			if inPerformance {
-				p.Sequence = pSeq
-				p.maybeInheritUnit(s, m)
-				m.Performances = append(m.Performances, p)
-				p = NewPerformance()
-				pSeq++
+			ps.inPerformance = true
+			return
+		}
+
+		if strings.HasSuffix(load, "%") {
+			f, err := floatValue(strings.TrimSuffix(load, "%"), "percent")
+
+			if err != nil {
+				ps.addError(tok, err)
 			}
-			inPerformance = false
-
-			if m.Name != "" {
-				m.Sequence = mSeq
-				s.Movements = append(s.Movements, m)
-				m = NewMovement()
-				mSeq++
-				pSeq = 0
+
+			if ps.cfg.validatePercents && f > 100 {
+				ps.addWarning(tok, fmt.Errorf("Percent of max over 100: %q", load))
 			}
 
-			m.Name = tok.Value()
+			ps.p.PercentOfMax = f
+			ps.p.PrescribedPercent = true
+			ps.inPerformance = true
+			return
+		}
+
+		min, max, err := loadRange(load)
+
+		if err != nil {
+			ps.addError(tok, err)
+		}
+
+		ps.p.Load, ps.p.LoadMin, ps.p.LoadMax = min, min, max
 
-			if tok.Name() == "MOVEMENT_SS" {
-				m.SuperSet = true
+		if unit != "" {
+			ps.p.Unit = unit
+		}
+		ps.inPerformance = true
+	case "METADATA":
+		for _, kv := range splitPairs(tok.Value()) {
+			ps.assignMetadata(tok, kv)
+		}
+	case "MODIFIER":
+		if !ps.inPerformance {
+			ps.addError(tok, fmt.Errorf("Load modifier before any performance: %q", tok.Value()))
+			return
+		}
+
+		if lc, ok := parseLoadComponent(tok.Value()); ok {
+			ps.p.addLoadComponent(lc)
+			ps.components = append(ps.components, tok)
+			return
+		}
+
+		lm, err := ParseLoadModifier(tok.Value())
+
+		if err != nil {
+			ps.addError(tok, err)
+			return
+		}
+
+		ps.p.LoadModifiers = append(ps.p.LoadModifiers, lm)
+	case "MOVEMENT", "MOVEMENT_SS":
+		ps.inSession = false
+
+		if ps.inPerformance {
+			ps.flushPerformance()
+		}
+		ps.inPerformance = false
+
+		if ps.m.Name != "" {
+			ps.flushMovement()
+		}
+
+		ps.m.Name = tok.Value()
+		if name, ok := ps.aliases[nameKey(ps.m.Name)]; ok {
+			ps.m.Name = name
+		}
+
+		if tok.Name() == "MOVEMENT_SS" {
+			ps.m.SuperSet = true
+		}
+	case "NOTE":
+		if ps.h != nil && ps.herr == nil {
+			ps.herr = ps.h.OnNote(ps.scope(), tok.Value())
+		}
+
+		if ps.inSession {
+			s.Notes = append(s.Notes, tok.Value())
+		} else if ps.set != nil {
+			ps.set.Notes = append(ps.set.Notes, tok.Value())
+		} else if ps.inPerformance {
+			ps.p.Notes = append(ps.p.Notes, tok.Value())
+		} else {
+			ps.m.Notes = append(ps.m.Notes, tok.Value())
+		}
+	case "REPS":
+		if ps.set != nil {
+			i, err := intValue(tok.Value(), "reps")
+
+			if err != nil {
+				ps.addError(tok, err)
 			}
-		case "NOTE":
-			if inSession {
-				s.Notes = append(s.Notes, tok.Value())
-			} else if inPerformance {
-				p.Notes = append(p.Notes, tok.Value())
-			} else {
-				m.Notes = append(m.Notes, tok.Value())
+
+			ps.set.Reps = i
+			return
+		}
+
+		ps.beginReps()
+		ps.repped = true
+
+		switch {
+		case strings.Contains(tok.Value(), "-"):
+			r, err := ParseRepRange(tok.Value())
+
+			if err != nil {
+				ps.addError(tok, err)
+				return
 			}
-		case "REPS":
-			i, err := intValue(tok.Value(), "reps")
+
+			ps.p.Reps = r.Min
+			ps.p.RepRange = &r
+		case strings.Contains(tok.Value(), ","):
+			reps, err := perSetReps(tok.Value())
 
 			if err != nil {
-				s.Errors = append(s.Errors, err)
+				ps.addError(tok, err)
+				return
 			}
 
-			p.Reps = i
-		case "SETS":
-			i, err := intValue(tok.Value(), "sets")
+			ps.p.PerSetReps = reps
+			ps.p.Sets = len(reps)
+			ps.p.Reps = 0
+			for _, r := range reps {
+				if r > ps.p.Reps {
+					ps.p.Reps = r
+				}
+			}
+		default:
+			i, err := intValue(tok.Value(), "reps")
 
 			if err != nil {
-				s.Errors = append(s.Errors, err)
+				ps.addError(tok, err)
 			}
 
-			p.Sets = i
+			ps.p.Reps = i
+		}
+	case "SCHEME":
+		ps.beginReps()
+		fields := strings.SplitN(tok.Value(), "x", 2)
+
+		sets, err := intValue(fields[0], "sets")
+
+		if err != nil {
+			ps.addError(tok, err)
+		}
+
+		reps, err := intValue(fields[1], "reps")
+
+		if err != nil {
+			ps.addError(tok, err)
+		}
+
+		ps.p.Sets = sets
+		ps.p.Reps = reps
+		ps.repped = true
+	case "SET":
+		if !ps.inPerformance {
+			ps.addError(tok, fmt.Errorf("Set before any performance"))
+			return
+		}
+
+		ps.set = &Set{Reps: ps.p.Reps}
+		ps.p.SetDetails = append(ps.p.SetDetails, ps.set)
+	case "SETS":
+		ps.beginUnloaded()
+		i, err := intValue(tok.Value(), "sets")
+
+		if err != nil {
+			ps.addError(tok, err)
+		}
+
+		ps.p.Sets = i
+	case "UNIT":
+		ps.assignMetadata(tok, "unit: "+tok.Value())
+	case "COMMENT":
+	default:
+		if ps.cfg.unknownTokens {
+			name := tok.Name()
+			if name == "" {
+				name = fmt.Sprintf("#%d", tok.Type())
+			}
+			ps.addError(tok, fmt.Errorf("Unknown token %s: %q", name, tok.Value()))
 		}
 	}
+}
+
+func (ps *parser) assignMetadata(tok *Token, kv string) {
+	key, value, ok := splitKeyValue(kv)
 
-	if p.Load != 0.0 {
-		p.Sequence = pSeq
-		p.maybeInheritUnit(s, m)
-		m.Performances = append(m.Performances, p)
+	if !ok {
+		ps.assignFlag(tok, unescape(strings.Trim(kv, " ")))
+		return
 	}
 
-	if m.Name != "" {
-		m.Sequence = mSeq
-		s.Movements = append(s.Movements, m)
+	key = unescape(strings.Trim(key, " "))
+	value = unescape(strings.Trim(value, " "))
+
+	if ps.h != nil && ps.herr == nil {
+		ps.herr = ps.h.OnMetadata(ps.scope(), key, value)
 	}
 
-	return s, nil
+	if ps.set != nil {
+		ps.setMetadata(tok, &ps.set.Metadata, key, value)
+	} else if ps.inSession {
+		if !ps.s.assignSpecial(key, value) {
+			ps.setMetadata(tok, &ps.s.Metadata, key, value)
+		}
+	} else if ps.inPerformance {
+		if !ps.p.assignSpecial(key, value) {
+			ps.setMetadata(tok, &ps.p.Metadata, key, value)
+		}
+
+		if err := ps.p.assignTyped(key, value); err != nil {
+			ps.addError(tok, err)
+		}
+	} else {
+		if !ps.m.assignSpecial(key, value) {
+			ps.setMetadata(tok, &ps.m.Metadata, key, value)
+		}
+	}
+}
+
+// assignFlag sets a metadata key given without a value, such as "# deload",
+// to true in the current scope.
+func (ps *parser) assignFlag(tok *Token, key string) {
+	if key == "" {
+		ps.addError(tok, fmt.Errorf("Failed to parse metadata: %q. Missing key", strings.TrimSpace(string(tok.t.Lexeme))))
+		return
+	}
+
+	if ps.h != nil && ps.herr == nil {
+		ps.herr = ps.h.OnMetadata(ps.scope(), key, "true")
+	}
+
+	md := &ps.m.Metadata
+	switch {
+	case ps.set != nil:
+		md = &ps.set.Metadata
+	case ps.inSession:
+		md = &ps.s.Metadata
+	case ps.inPerformance:
+		md = &ps.p.Metadata
+	}
+
+	ps.setMetadata(tok, md, key, true)
+}
+
+// parseDate reads v with the configured layout, falling back to dateparse.
+// The layout is recorded on s when it was the one used.
+func (ps *parser) parseDate(s *Session, v string) (time.Time, error) {
+	s.DateLayout = ""
+
+	if ps.cfg.dateLayout != "" {
+		if d, err := time.Parse(ps.cfg.dateLayout, v); err == nil {
+			s.DateLayout = ps.cfg.dateLayout
+			return d, nil
+		}
+	}
+
+	return dateparse.ParseAny(v)
+}
+
+// beginReps begins a new performance when the current one already has reps.
+func (ps *parser) beginReps() {
+	if ps.repped && ps.inPerformance {
+		ps.flushPerformance()
+	}
+	ps.beginUnloaded()
+}
+
+// beginUnloaded starts a bodyweight performance when reps, sets, or fails
+// are given in a movement without a load.
+func (ps *parser) beginUnloaded() {
+	ps.inPerformance = true
+}
+
+// extendSpans grows the spans of the current Performance and Movement to
+// cover tok.
+func (ps *parser) extendSpans(tok *Token) {
+	start, end := tok.Span()
+	startLine, _ := tok.Start()
+	endLine, _ := tok.End()
+	ts := SourceSpan{start, end, startLine, endLine}
+
+	if ps.inPerformance {
+		ps.p.Span = ps.p.Span.extend(ts)
+	}
+
+	if !ps.inSession {
+		ps.m.Span = ps.m.Span.extend(ts)
+	}
+}
+
+// collectAliases defines the aliases of every ALIAS token ahead of parsing,
+// so that an alias applies to movements on either side of it. The first
+// definition of an alias wins, and one without a name is skipped.
+func (ps *parser) collectAliases(tokens []*Token) {
+	for _, tok := range tokens {
+		if tok.Name() != "ALIAS" {
+			continue
+		}
+
+		alias, name := splitAlias(tok.Value())
+		if name == "" {
+			continue
+		}
+
+		if ps.aliases == nil {
+			ps.aliases = make(map[string]string)
+		}
+
+		if _, ok := ps.aliases[nameKey(alias)]; !ok {
+			ps.aliases[nameKey(alias)] = name
+		}
+	}
+}
+
+// addError records err on the current Session at the position of tok.
+func (ps *parser) addError(tok *Token, err error) {
+	line, col := tok.Start()
+	ps.s.Errors = append(ps.s.Errors, &ParseError{Line: line, Col: col, Msg: err.Error()})
+}
+
+// addWarning records err on the current Session as a warning.
+func (ps *parser) addWarning(tok *Token, err error) {
+	line, col := tok.Start()
+	ps.s.Errors = append(ps.s.Errors, &ParseError{Line: line, Col: col, Msg: err.Error(), Warning: true})
+}
+
+// setMetadata stores a metadata pair, applying any key normalizer and value
+// coercion from the config.
+func (ps *parser) setMetadata(tok *Token, md *Metadata, key string, value interface{}) {
+	if ps.cfg.keyNormalizer != nil {
+		key = ps.cfg.keyNormalizer(key)
+	}
+
+	if _, ok := (*md)[key]; ok && ps.cfg.duplicateKeys {
+		ps.addWarning(tok, fmt.Errorf("Duplicate metadata key: %q", key))
+	}
+
+	if v, ok := value.(string); ok && ps.cfg.typedMetadata {
+		md.Set(key, coerceValue(v))
+	} else {
+		md.Set(key, value)
+	}
+}
+
+// finish flushes any pending state and returns the parsed sessions.
+func (ps *parser) finish() []*Session {
+	ps.endSession()
+	return ps.sessions
+}
+
+// scope is where metadata and notes given now belong.
+func (ps *parser) scope() Scope {
+	if ps.inSession {
+		return SessionScope
+	} else if ps.set != nil {
+		return SetScope
+	} else if ps.inPerformance {
+		return PerformanceScope
+	}
+	return MovementScope
+}
+
+func (ps *parser) flushPerformance() {
+	ps.p.collapseSets()
+	ps.set = nil
+	ps.pSeq++
+	ps.p.Sequence = ps.pSeq
+	if !ps.cfg.noUnitInheritance {
+		ps.p.maybeInheritUnit(ps.s, ps.m)
+	}
+	for i, err := range ps.p.sumLoadComponents() {
+		if err != nil {
+			ps.addError(ps.components[i], err)
+		}
+	}
+	ps.components = nil
+	if ps.h == nil {
+		ps.m.Performances = append(ps.m.Performances, ps.p)
+	} else if ps.herr == nil {
+		ps.herr = ps.h.OnPerformance(ps.m, ps.p)
+	}
+	ps.prev = ps.p
+	ps.p = newPerformance()
+	ps.repped = false
+}
+
+func (ps *parser) flushMovement() {
+	ps.mSeq++
+	ps.m.Sequence = ps.mSeq
+	if ps.h == nil {
+		ps.s.Movements = append(ps.s.Movements, ps.m)
+	} else if ps.herr == nil {
+		ps.herr = ps.h.OnMovement(ps.m)
+	}
+	ps.m = newMovement()
+	ps.prev = nil
+	ps.pSeq = 0
+}
+
+func (ps *parser) endSession() {
+	if ps.inPerformance {
+		ps.flushPerformance()
+	}
+
+	if ps.m.Name != "" {
+		ps.flushMovement()
+	}
+
+	if !ps.dated {
+		if !ps.cfg.defaultDate.IsZero() {
+			ps.s.Date = ps.cfg.defaultDate
+		} else if ps.split {
+			// A leading block without a date line is assumed to be from today.
+			ps.s.Date = today()
+		}
+	}
+
+	if bw, ok := ps.s.Bodyweight(); ok {
+		ps.s.ResolveRelativeLoads(bw)
+	}
+
+	if ps.cfg.numericGuards {
+		ps.s.Errors = append(ps.s.Errors, ps.s.ValidateWith(NoNegativeValues, NoAbsurdLoads)...)
+	}
+
+	if ps.cfg.roundLoads {
+		ps.s.RoundLoads(ps.cfg.loadPrecision)
+	}
+
+	if ps.cfg.percentages {
+		ps.s.ComputePercentages()
+	}
+
+	if ps.h == nil {
+		ps.sessions = append(ps.sessions, ps.s)
+	} else if ps.herr == nil {
+		ps.herr = ps.h.OnSession(ps.s)
+	}
+
+	ps.s = ps.cfg.newSession()
+	ps.p = newPerformance()
+	ps.dated = false
+	ps.inSession = true
+	ps.inPerformance = false
+	ps.repped = false
+	ps.mSeq = 0
+	ps.pSeq = 0
 }
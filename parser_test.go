@@ -1,8 +1,13 @@
 package traindown
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -42,10 +47,10 @@ func parseCheck(t *testing.T, b []byte, s string) {
 	m2Meta := Metadata{"movement key": "movement value"}
 
 	if m1.Name != "movement" ||
-		m1.Sequence != 0 ||
+		m1.Sequence != 1 ||
 		len(m1p) != 1 ||
 		fmt.Sprint(m1p0.Metadata) != fmt.Sprint(m1p0Meta) ||
-		m1p0.Sequence != 0 ||
+		m1p0.Sequence != 1 ||
 		len(m1p0.Notes) != 1 ||
 		m1p0.Notes[0] != "performance note" ||
 		m1p0.Load != 100 ||
@@ -57,18 +62,18 @@ func parseCheck(t *testing.T, b []byte, s string) {
 	}
 
 	if m2.Name != "another" ||
-		m2.Sequence != 1 ||
+		m2.Sequence != 2 ||
 		m2.SuperSet != true ||
 		len(m2p) != 2 ||
 		fmt.Sprint(m2.Metadata) != fmt.Sprint(m2Meta) ||
 		len(m2.Notes) != 1 ||
 		m2.Notes[0] != "movement note" ||
-		m2p0.Sequence != 0 ||
+		m2p0.Sequence != 1 ||
 		m2p0.Load != 200.1 ||
 		m2p0.Reps != 1 ||
 		m2p0.Sets != 1 ||
 		m2p0.Unit != "session" ||
-		m2p1.Sequence != 1 ||
+		m2p1.Sequence != 2 ||
 		m2p1.Load != 200.2 ||
 		m2p1.Reps != 1 ||
 		m2p1.Sets != 2 ||
@@ -150,3 +155,1225 @@ func TestParseUnit(t *testing.T) {
 		}
 	}
 }
+
+func TestParseReader(t *testing.T) {
+	f, err := os.Open("./testdata")
+
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	defer f.Close()
+
+	session, err := ParseReader(f)
+
+	if err != nil {
+		t.Fatalf("Failed to parse reader: %q", err)
+	}
+
+	if len(session.Movements) != 2 {
+		t.Errorf("Expected 2 movements, got %d", len(session.Movements))
+	}
+
+	session, err = ParseReader(strings.NewReader(""))
+
+	if err != ErrEmptyInput {
+		t.Errorf("Expected ErrEmptyInput for empty reader, got %v", err)
+	}
+
+	if session == nil || len(session.Movements) != 0 || len(session.Metadata) != 0 {
+		t.Errorf("Expected an empty session, got %v", session)
+	}
+}
+
+func TestParseSessions(t *testing.T) {
+	text := `
+    squat:
+      100 5r
+
+    @ 2020-01-01
+    * first
+    bench:
+      200 3r
+    + row:
+      150
+
+    @ 2020-01-02
+    deadlift:
+      300 1r`
+
+	sessions, err := ParseSessions(text)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if len(sessions) != 3 {
+		t.Fatalf("Expected 3 sessions, got %d", len(sessions))
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	if sessions[0].Date != today ||
+		len(sessions[0].Movements) != 1 ||
+		sessions[0].Movements[0].Name != "squat" {
+		t.Errorf("Failed to parse undated session: %v", sessions[0])
+	}
+
+	s1 := sessions[1]
+	if s1.Date != time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC) ||
+		len(s1.Notes) != 1 ||
+		len(s1.Movements) != 2 ||
+		s1.Movements[1].Name != "row" ||
+		!s1.Movements[1].SuperSet ||
+		len(s1.Movements[1].Performances) != 1 {
+		t.Errorf("Failed to parse first dated session: %v", s1)
+	}
+
+	s2 := sessions[2]
+	if s2.Date != time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC) ||
+		len(s2.Movements) != 1 ||
+		s2.Movements[0].Name != "deadlift" ||
+		s2.Movements[0].Performances[0].Load != 300 {
+		t.Errorf("Failed to parse second dated session: %v", s2)
+	}
+
+	sessions, err = ParseSessions("")
+
+	if err != nil || len(sessions) != 0 {
+		t.Errorf("Expected no sessions for empty input, got %v (%v)", sessions, err)
+	}
+
+	single, err := ParseString(text)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if len(single.Movements) != 4 {
+		t.Errorf("Expected ParseString to merge all movements, got %d", len(single.Movements))
+	}
+}
+
+func TestParseMetadataWithColons(t *testing.T) {
+	text := `
+    # url: https://example.com/lift
+    # start: 14:30
+    movement:
+      100
+        # time: 14:35:10`
+
+	session, err := ParseString(text)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if session.Metadata["url"] != "https://example.com/lift" {
+		t.Errorf("Incorrect url: %q", session.Metadata["url"])
+	}
+
+	if session.Metadata["start"] != "14:30" {
+		t.Errorf("Incorrect start: %q", session.Metadata["start"])
+	}
+
+	p := session.Movements[0].Performances[0]
+	if p.Metadata["time"] != "14:35:10" {
+		t.Errorf("Incorrect time: %q", p.Metadata["time"])
+	}
+}
+
+func TestParseMetadataFlags(t *testing.T) {
+	text := `
+    # pr
+    # key: value
+    movement:
+      # unilateral
+      100
+        # deload
+    #`
+
+	session, err := ParseString(text)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if len(session.Errors) != 1 || !strings.HasSuffix(session.Errors[0].Error(), `Failed to parse metadata: "#". Missing key`) {
+		t.Errorf("Expected an error for the empty key, got %q", session.Errors)
+	}
+
+	if len(session.Metadata) != 2 || session.Metadata["pr"] != true || !session.Metadata.Has("pr") || session.Metadata["key"] != "value" {
+		t.Errorf("Incorrect session metadata: %v", session.Metadata)
+	}
+
+	if m := session.Movements[0]; m.Metadata["unilateral"] != true {
+		t.Errorf("Incorrect movement metadata: %v", m.Metadata)
+	}
+
+	p := session.Movements[0].Performances[0]
+	if b, ok := p.Metadata.Bool("deload"); !ok || !b || !p.Metadata.Has("deload") || p.Metadata.Has("pr") || p.Load != 100 {
+		t.Errorf("Incorrect performance: %v", p)
+	}
+
+	b, err := session.Marshal()
+
+	if err != nil {
+		t.Fatalf("Failed to marshal: %q", err)
+	}
+
+	if !strings.Contains(string(b), "\n# pr\n") || !strings.Contains(string(b), "    # deload\n") {
+		t.Errorf("Expected flags to be written without values:\n%s", b)
+	}
+}
+
+func TestParseLoadUnit(t *testing.T) {
+	text := `
+    # unit: session
+    movement:
+      100kg
+      225 lb 5r
+      60`
+
+	session, err := ParseString(text)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if len(session.Errors) != 0 {
+		t.Fatalf("Errors on session: %q", session.Errors)
+	}
+
+	ps := session.Movements[0].Performances
+	expected := []struct {
+		load float32
+		unit string
+		reps int
+	}{
+		{100, "kg", 1},
+		{225, "lb", 5},
+		{60, "session", 1},
+	}
+
+	if len(ps) != len(expected) {
+		t.Fatalf("Expected %d performances, got %d", len(expected), len(ps))
+	}
+
+	for i, ex := range expected {
+		if ps[i].Load != ex.load || ps[i].Unit != ex.unit || ps[i].Reps != ex.reps {
+			t.Errorf("Incorrect performance %d: %v", i, ps[i])
+		}
+	}
+}
+
+func TestParseSequences(t *testing.T) {
+	text := `
+    squat:
+      100
+      200 2r
+      300 3r
+    + bench:
+      50
+      60`
+
+	session, err := ParseString(text)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	for mi, m := range session.Movements {
+		if m.Sequence != mi+1 {
+			t.Errorf("Incorrect sequence for %q: %d", m.Name, m.Sequence)
+		}
+
+		for pi, p := range m.Performances {
+			if p.Sequence != pi+1 {
+				t.Errorf("Incorrect sequence for %q performance %d: %d", m.Name, pi, p.Sequence)
+			}
+		}
+	}
+
+	if len(session.Movements) != 2 ||
+		len(session.Movements[0].Performances) != 3 ||
+		len(session.Movements[1].Performances) != 2 {
+		t.Errorf("Incorrect shape: %v", session)
+	}
+}
+
+func TestParseStringStrict(t *testing.T) {
+	session, err := ParseStringStrict("movement:\n100\n# key: value")
+
+	if err != nil {
+		t.Errorf("Unexpected error: %q", err)
+	}
+
+	if len(session.Movements) != 1 {
+		t.Errorf("Failed to parse session: %v", session)
+	}
+
+	session, err = ParseStringStrict("@ not a date\nmovement:\n100 5.5r")
+
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	errs, ok := err.(ParseErrors)
+	if !ok || len(errs) != 2 || len(session.Errors) != 2 {
+		t.Errorf("Expected 2 aggregated errors, got %q", err)
+	}
+
+	if !strings.HasPrefix(err.Error(), "2 parse error(s): ") {
+		t.Errorf("Unexpected message: %q", err.Error())
+	}
+
+	session, err = ParseString("@ not a date\nmovement:\n100 5.5r")
+
+	if err != nil || len(session.Errors) != 2 {
+		t.Errorf("Expected lenient parsing, got %q (%q)", err, session.Errors)
+	}
+}
+
+func TestParseInvalidDate(t *testing.T) {
+	session, err := ParseString("@ not a date\nmovement:\n100")
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if len(session.Errors) != 1 {
+		t.Errorf("Expected a date error, got %q", session.Errors)
+	}
+
+	now := time.Now().UTC()
+	expected := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	if session.Date != expected {
+		t.Errorf("Expected %v, got %v", expected, session.Date)
+	}
+}
+
+func TestParseRPE(t *testing.T) {
+	text := `
+    movement:
+      100
+        # rpe: 8
+      110
+        # RPE: 9.5
+      120
+        # rpe: hard`
+
+	session, err := ParseString(text)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	ps := session.Movements[0].Performances
+
+	if ps[0].RPE != 8 || ps[0].Metadata["rpe"] != "8" {
+		t.Errorf("Failed to parse integer RPE: %v", ps[0])
+	}
+
+	if ps[1].RPE != 9.5 || ps[1].Metadata["RPE"] != "9.5" {
+		t.Errorf("Failed to parse decimal RPE: %v", ps[1])
+	}
+
+	if ps[2].RPE != 0 || ps[2].Metadata["rpe"] != "hard" || len(session.Errors) != 1 {
+		t.Errorf("Failed to record invalid RPE: %v (%q)", ps[2], session.Errors)
+	}
+}
+
+func TestParseTempoMetadata(t *testing.T) {
+	text := `
+    movement:
+      100
+        # tempo: 3-1-X-0
+      110
+        # tempo: slow`
+
+	session, err := ParseString(text)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	ps := session.Movements[0].Performances
+
+	if ps[0].Tempo == nil || *ps[0].Tempo != (Tempo{3, 1, 0, 0}) || ps[0].Metadata["tempo"] != "3-1-X-0" {
+		t.Errorf("Failed to parse tempo: %v", ps[0])
+	}
+
+	if ps[1].Tempo != nil || len(session.Errors) != 1 {
+		t.Errorf("Failed to record invalid tempo: %v (%q)", ps[1], session.Errors)
+	}
+}
+
+func TestParseRest(t *testing.T) {
+	text := `
+    movement:
+      100
+        # rest: 90s
+      110
+        # rest: 2m
+      120
+        # rest: 45
+      130
+      140
+        # rest: a while
+      150
+        # rest: PT1M30S
+      160
+        # rest: pt2.5m
+      170
+        # rest: PT1M30
+      180
+        # rest: P1M`
+
+	session, err := ParseString(text)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	ps := session.Movements[0].Performances
+	expected := []time.Duration{90 * time.Second, 2 * time.Minute, 45 * time.Second, 0, 0, 90 * time.Second, 150 * time.Second, 0, 0}
+
+	for i, d := range expected {
+		if ps[i].Rest != d {
+			t.Errorf("Incorrect rest for %d: %v", i, ps[i].Rest)
+		}
+	}
+
+	if len(session.Errors) != 3 || ps[4].Metadata["rest"] != "a while" || ps[7].Metadata["rest"] != "PT1M30" {
+		t.Errorf("Failed to record invalid rest: %v (%q)", ps[4], session.Errors)
+	}
+}
+
+func TestISODuration(t *testing.T) {
+	cases := map[string]time.Duration{
+		"PT90S":     90 * time.Second,
+		"PT1H2M3S":  time.Hour + 2*time.Minute + 3*time.Second,
+		"P1DT12H":   36 * time.Hour,
+		"P1W":       7 * 24 * time.Hour,
+		"PT0.5S":    500 * time.Millisecond,
+		"PT1.5M30S": 0,
+		"PT1S2M":    0,
+		"P1Y":       0,
+		"P":         0,
+		"PT":        0,
+		"P1DT":      0,
+		"PT1MT1S":   0,
+		"P1H":       0,
+	}
+
+	for s, expected := range cases {
+		d, ok := isoDuration(s)
+
+		if ok != (expected != 0) || d != expected {
+			t.Errorf("Unexpected duration for %q: %v %v", s, d, ok)
+		}
+	}
+}
+
+func TestParseComments(t *testing.T) {
+	text := `
+    // a full line comment
+    movement: // trailing a movement
+      // between performances
+      100 2r 3s // trailing a load
+      200 // 4r`
+
+	session, err := ParseString(text)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if len(session.Errors) != 0 || len(session.Notes) != 0 || len(session.Metadata) != 0 {
+		t.Errorf("Comment leaked into session: %v", session)
+	}
+
+	ps := session.Movements[0].Performances
+
+	if len(ps) != 2 ||
+		ps[0].Load != 100 || ps[0].Reps != 2 || ps[0].Sets != 3 ||
+		ps[1].Load != 200 || ps[1].Reps != 1 {
+		t.Errorf("Comment corrupted performances: %v", ps)
+	}
+}
+
+func TestParseFileByPath(t *testing.T) {
+	session, err := ParseFile("./testdata")
+
+	if err != nil {
+		t.Fatalf("Failed to parse file: %v", err)
+	}
+
+	if len(session.Movements) != 2 || session.Movements[1].Name != "another" {
+		t.Errorf("Failed to parse file: %v", session)
+	}
+
+	_, err = ParseFile("./missing")
+
+	if !errors.Is(err, os.ErrNotExist) || !strings.Contains(err.Error(), "./missing") {
+		t.Errorf("Expected a not exist error naming the file, got %v", err)
+	}
+}
+
+func TestParsePercentLoads(t *testing.T) {
+	text := `
+    movement:
+      100
+      80% 5r
+      120%`
+
+	session, err := ParseStringWithOptions(text, WithPercentages(true))
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if len(session.Errors) != 0 {
+		t.Errorf("Unexpected errors: %q", session.Errors)
+	}
+
+	ps := session.Movements[0].Performances
+
+	if len(ps) != 3 {
+		t.Fatalf("Expected 3 performances, got %d", len(ps))
+	}
+
+	if ps[0].PrescribedPercent || ps[0].PercentOfMax != 100 {
+		t.Errorf("Incorrect computed percentage: %v", ps[0])
+	}
+
+	if !ps[1].PrescribedPercent || ps[1].PercentOfMax != 80 || ps[1].Load != 0 || ps[1].Reps != 5 {
+		t.Errorf("Incorrect prescribed percentage: %v", ps[1])
+	}
+
+	if !ps[2].PrescribedPercent || ps[2].PercentOfMax != 120 {
+		t.Errorf("Incorrect prescribed percentage: %v", ps[2])
+	}
+
+	session, err = ParseStringWithOptions(text, WithPercentValidation(true))
+
+	if err != nil || len(session.Errors) != 1 {
+		t.Errorf("Expected a validation error, got %q (%v)", session.Errors, err)
+	}
+}
+
+func TestParseErrorPositions(t *testing.T) {
+	text := "@ not a date\nmovement:\n  100\n    # rpe: hard\n  5.5r"
+
+	session, err := ParseString(text)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	expected := []ParseError{
+		{Line: 1, Col: 1},
+		{Line: 4, Col: 5},
+		{Line: 5, Col: 3},
+	}
+
+	if len(session.Errors) != len(expected) {
+		t.Fatalf("Expected %d errors, got %q", len(expected), session.Errors)
+	}
+
+	for i, ex := range expected {
+		var pe *ParseError
+		if !errors.As(session.Errors[i], &pe) || pe.Line != ex.Line || pe.Col != ex.Col {
+			t.Errorf("Incorrect position for %q", session.Errors[i])
+		}
+	}
+
+	msg := session.Errors[1].Error()
+	if msg != `4:5: Failed to parse "rpe": "hard"` {
+		t.Errorf("Incorrect message: %q", msg)
+	}
+}
+
+func TestParseMultipleMetadataPairs(t *testing.T) {
+	text := `
+    # start: 14:30, url: https://example.com/lift
+    movement:
+      100
+        # rpe: 8, tempo: 3010`
+
+	session, err := ParseString(text)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if session.Metadata["start"] != "14:30" || session.Metadata["url"] != "https://example.com/lift" {
+		t.Errorf("Incorrect session metadata: %v", session.Metadata)
+	}
+
+	p := session.Movements[0].Performances[0]
+
+	if p.RPE != 8 || p.Tempo == nil || *p.Tempo != (Tempo{3, 0, 1, 0}) || len(p.Metadata) != 2 {
+		t.Errorf("Incorrect performance metadata: %v", p)
+	}
+}
+
+func TestParseCRLF(t *testing.T) {
+	session, err := ParseString("@ 2020-01-01\r\n# key: value\r\n* session note\r\n\r\nsquat:\r\n  * movement note\r\n  100 5r\r\n    # rpe: 8\r\n")
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if session.Metadata["key"] != "value" || session.Notes[0] != "session note" {
+		t.Errorf("Carriage return in session fields: %q %q", session.Metadata, session.Notes)
+	}
+
+	m := session.Movements[0]
+
+	if m.Name != "squat" || m.Notes[0] != "movement note" || m.Performances[0].Metadata["rpe"] != "8" {
+		t.Errorf("Carriage return in movement fields: %v", m)
+	}
+}
+
+func TestParseEmptyInput(t *testing.T) {
+	for _, text := range []string{"", "   ", "\n\t\r\n", "// just a comment\n"} {
+		session, err := ParseString(text)
+
+		if err != ErrEmptyInput {
+			t.Errorf("Expected ErrEmptyInput for %q, got %v", text, err)
+		}
+
+		if session == nil || session.Metadata == nil || len(session.Movements) != 0 {
+			t.Errorf("Expected an empty session for %q, got %v", text, session)
+		}
+	}
+
+	if _, err := ParseByte([]byte(" ")); err != ErrEmptyInput {
+		t.Errorf("Expected ErrEmptyInput from ParseByte, got %v", err)
+	}
+
+	if _, err := ParseString("# key: value"); err != nil {
+		t.Errorf("Metadata only input reported as empty: %v", err)
+	}
+}
+
+func TestParseBodyweight(t *testing.T) {
+	session, err := ParseString(`
+    pullups:
+      10r
+        * strict
+      8r 2s
+
+    dips:
+      0 12r`)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	pullups := session.Movements[0].Performances
+
+	if len(pullups) != 2 ||
+		pullups[0].Reps != 10 || pullups[0].Notes[0] != "strict" ||
+		pullups[1].Reps != 8 || pullups[1].Sets != 2 {
+		t.Fatalf("Failed to parse reps only performances: %v", session.Movements[0])
+	}
+
+	dips := session.Movements[1].Performances
+
+	if len(dips) != 1 || dips[0].Reps != 12 {
+		t.Fatalf("Failed to parse zero load performance: %v", session.Movements[1])
+	}
+
+	for _, p := range append(pullups, dips...) {
+		if !p.Unloaded() {
+			t.Errorf("Expected an unloaded performance: %v", p)
+		}
+	}
+}
+
+func TestParseTrailingZeroLoad(t *testing.T) {
+	text := `
+    @ 2020-01-01
+
+    squat:
+      100 5r
+      0 5r
+
+    @ 2020-01-02
+
+    squat:
+      100 5r
+      0 5r`
+
+	session, err := ParseString(text)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	ps := session.Movements[len(session.Movements)-1].Performances
+
+	if len(ps) != 2 || ps[1].Load != 0 || ps[1].Reps != 5 || ps[1].Sequence != 2 {
+		t.Errorf("Dropped trailing zero load performance: %v", ps)
+	}
+
+	sessions, err := ParseSessions(text)
+
+	if err != nil {
+		t.Fatalf("Failed to parse sessions: %q", err)
+	}
+
+	for _, s := range sessions {
+		if n := len(s.Movements[0].Performances); n != 2 {
+			t.Errorf("Expected 2 performances on %v, got %d", s.Date, n)
+		}
+	}
+}
+
+func TestParseScheme(t *testing.T) {
+	session, err := ParseString(`
+    squat:
+      225 5x5
+      135 3x10 1f
+      95 8r 2s
+      3x8`)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	expected := []struct {
+		load  float32
+		reps  int
+		sets  int
+		fails int
+	}{
+		{225, 5, 5, 0},
+		{135, 10, 3, 1},
+		{95, 8, 2, 0},
+		{0, 8, 3, 0},
+	}
+
+	ps := session.Movements[0].Performances
+
+	if len(ps) != len(expected) {
+		t.Fatalf("Expected %d performances, got %d: %v", len(expected), len(ps), ps)
+	}
+
+	for i, ex := range expected {
+		p := ps[i]
+		if p.Load != ex.load || p.Reps != ex.reps || p.Sets != ex.sets || p.Fails != ex.fails {
+			t.Errorf("Mismatch at %d: %v", i, p)
+		}
+	}
+}
+
+func TestParseRepRanges(t *testing.T) {
+	session, err := ParseString(`
+    curl:
+      40 8-12r 3s
+      40 10r
+      40 12-8r`)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	ps := session.Movements[0].Performances
+
+	if len(ps) != 3 {
+		t.Fatalf("Expected 3 performances, got %v", ps)
+	}
+
+	if r := ps[0].RepRange; r == nil || r.Min != 8 || r.Max != 12 || ps[0].Reps != 8 || ps[0].MaxReps() != 12 || ps[0].Sets != 3 {
+		t.Errorf("Failed to parse rep range: %v", ps[0])
+	}
+
+	if ps[1].RepRange != nil || ps[1].MaxReps() != 10 {
+		t.Errorf("Expected a single rep count: %v", ps[1])
+	}
+
+	if len(session.Errors) != 1 || !strings.Contains(session.Errors[0].Error(), "Inverted rep range") {
+		t.Errorf("Expected an inverted range error, got %v", session.Errors)
+	}
+
+	b, err := session.Marshal()
+
+	if err != nil || !strings.Contains(string(b), "  40 8-12r 3s\n") {
+		t.Errorf("Rep range not preserved:\n%s", b)
+	}
+}
+
+func TestParseTitle(t *testing.T) {
+	session, err := ParseString("@ 2020-01-01\n# Title: Heavy day\n* session note\nsquat:\n  # title: not a session title\n  100")
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if session.Title != "Heavy day" || len(session.Metadata) != 0 {
+		t.Errorf("Failed to parse title: %q %v", session.Title, session.Metadata)
+	}
+
+	if session.Movements[0].Metadata["title"] != "not a session title" {
+		t.Errorf("Expected movement title metadata: %v", session.Movements[0].Metadata)
+	}
+
+	session, err = ParseString("squat:\n  100")
+
+	if err != nil || session.Title != "" {
+		t.Errorf("Expected no title: %q %v", session.Title, err)
+	}
+}
+
+func TestParseMarkerCase(t *testing.T) {
+	expected := Performance{Load: 225, Reps: 5, Sets: 3, Fails: 1}
+
+	for _, line := range []string{
+		"225 5r 3s 1f",
+		"225 5R 3S 1F",
+		"225 R 5 S 3 F 1",
+		"225 r5 s3 f1",
+		"225 R5 3s F 1",
+	} {
+		session, err := ParseString("squat:\n  " + line)
+
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %q", line, err)
+		}
+
+		ps := session.Movements[0].Performances
+
+		if len(ps) != 1 || len(session.Errors) != 0 {
+			t.Errorf("Expected a single performance from %q, got %v %v", line, ps, session.Errors)
+			continue
+		}
+
+		p := ps[0]
+		if p.Load != expected.Load || p.Reps != expected.Reps || p.Sets != expected.Sets || p.Fails != expected.Fails {
+			t.Errorf("Mismatch for %q: %v", line, p)
+		}
+	}
+
+	session, err := ParseString("curl:\n  40 R 8-12")
+
+	if err != nil || session.Movements[0].Performances[0].MaxReps() != 12 {
+		t.Errorf("Failed to parse a leading marker rep range: %v %v", session, err)
+	}
+}
+
+func TestParseDecimalReps(t *testing.T) {
+	for _, line := range []string{"225 5r 3s", "225 5.0r 3.0s", "225 r5.0 s 3.00"} {
+		session, err := ParseString("squat:\n  " + line)
+
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %q", line, err)
+		}
+
+		p := session.Movements[0].Performances[0]
+		if len(session.Errors) != 0 || p.Reps != 5 || p.Sets != 3 {
+			t.Errorf("Mismatch for %q: %v %v", line, p, session.Errors)
+		}
+	}
+
+	session, err := ParseString("squat:\n  225 5.5r")
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if len(session.Errors) != 1 || !strings.Contains(session.Errors[0].Error(), `"5.5"`) {
+		t.Errorf("Expected an error for fractional reps, got %v", session.Errors)
+	}
+}
+
+func TestParseLoadRanges(t *testing.T) {
+	session, err := ParseString(`
+    squat:
+      225-245lb r 3
+      100.5kg 5r
+      245-225 1r
+      70-80% 5r`)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	ps := session.Movements[0].Performances
+
+	if len(ps) != 4 {
+		t.Fatalf("Expected 4 performances, got %v", ps)
+	}
+
+	if p := ps[0]; p.Load != 225 || p.LoadMin != 225 || p.LoadMax != 245 || p.Reps != 3 {
+		t.Errorf("Unexpected load range: %v", p)
+	}
+
+	if p := ps[1]; p.Load != 100.5 || p.LoadMin != 100.5 || p.LoadMax != 100.5 || p.Unit != "kg" {
+		t.Errorf("Expected a plain load to be a range of one: %v", p)
+	}
+
+	if p := ps[2]; p.Load != 245 || p.LoadMax != 245 {
+		t.Errorf("Expected an inverted range to keep its first load: %v", p)
+	}
+
+	if len(session.Errors) != 2 ||
+		session.Errors[0].Error() != `5:7: Inverted load range: "245-225"` ||
+		!strings.Contains(session.Errors[1].Error(), `"70-80"`) {
+		t.Errorf("Unexpected errors: %v", session.Errors)
+	}
+
+	session.Errors = nil
+	b, err := session.Marshal()
+
+	if err != nil {
+		t.Fatalf("Failed to marshal: %q", err)
+	}
+
+	if !strings.Contains(string(b), "  225-245 3r\n") {
+		t.Errorf("Expected the range to be written back:\n%s", b)
+	}
+
+	if p, err := ps[0].ConvertTo("kg"); err != nil || p.LoadMax <= p.LoadMin || p.LoadMin != p.Load {
+		t.Errorf("Failed to convert a load range: %v %v", p, err)
+	}
+}
+
+func TestParseSameLoad(t *testing.T) {
+	session, err := ParseString(`
+    squat:
+      100kg 5r
+      same r 5
+      " 3r
+      SAME 2r
+
+    bench:
+      same 5r
+      80 +10band 5r
+      same 3r`)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	ps := session.Movements[0].Performances
+
+	if len(ps) != 4 {
+		t.Fatalf("Expected 4 performances, got %v", ps)
+	}
+
+	for i, p := range ps {
+		if p.Load != 100 || p.Unit != "kg" {
+			t.Errorf("Expected performance %d to repeat the load: %v", i, p)
+		}
+	}
+
+	if ps[1].Reps != 5 || ps[2].Reps != 3 || ps[3].Reps != 2 {
+		t.Errorf("Unexpected reps: %v", ps)
+	}
+
+	bench := session.Movements[1].Performances
+
+	if len(bench) != 3 || bench[0].Load != 0 || bench[2].Load != 80 || len(bench[2].LoadModifiers) != 1 {
+		t.Errorf("Unexpected bench: %v", bench)
+	}
+
+	if len(session.Errors) != 1 || session.Errors[0].Error() != "9:7: Same load without a previous performance" {
+		t.Errorf("Expected an error for the first bench: %v", session.Errors)
+	}
+
+	for _, txt := range []string{"squat:\n  100 5r same\n", "squat:\n  100 5r \" 3r\n", "squat:\n  same5r\n"} {
+		if _, err := ParseString(txt); err == nil {
+			t.Errorf("Expected a lexer error for %q", txt)
+		}
+	}
+}
+
+func TestParseAliases(t *testing.T) {
+	sessions, err := ParseSessions(`
+    alias bp = Bench Press
+    ALIAS ohp=Overhead Press
+
+    @ 2020-01-01
+    BP:
+      100 5r
+    + ohp:
+      50 5r
+    squat:
+      140 5r
+
+    @ 2020-01-02
+    alias sq = Back Squat
+    alias bp = Bicep Curl
+    sq:
+      150 5r
+    bp:
+      105 5r`)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	var names []string
+	for _, s := range sessions {
+		for _, m := range s.Movements {
+			names = append(names, m.Name)
+		}
+	}
+
+	if strings.Join(names, ",") != "Bench Press,Overhead Press,squat,Back Squat,Bench Press" {
+		t.Errorf("Unexpected movements: %v", names)
+	}
+
+	if !sessions[0].Movements[1].SuperSet {
+		t.Errorf("Expected an aliased superset: %v", sessions[0].Movements[1])
+	}
+
+	if errs := sessions[1].Errors; len(errs) != 1 || !IsWarning(errs[0]) || !strings.Contains(errs[0].Error(), `redefined, keeping the first: "bp"`) {
+		t.Errorf("Expected a redefinition warning: %v", errs)
+	}
+
+	if _, err := ParseString("alias bp = Bench Press"); err != ErrEmptyInput {
+		t.Errorf("Expected aliases alone to be empty: %v", err)
+	}
+}
+
+func TestParseUndefinedAndEmptyAliases(t *testing.T) {
+	session, err := ParseString(`
+    alias bp = Bench Press
+    alias dl =
+    @ 2020-01-01
+    dl:
+      200 5r
+    ohp:
+      50 5r
+    Bench Press:
+      100 5r
+    row:
+      80 5r
+    Squat:
+      140 5r`)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	var names []string
+	for _, m := range session.Movements {
+		names = append(names, m.Name)
+	}
+
+	if strings.Join(names, ",") != "dl,ohp,Bench Press,row,Squat" {
+		t.Errorf("Unexpected movements: %v", names)
+	}
+
+	errs := session.Errors
+	if len(errs) != 1 || IsWarning(errs[0]) || errs[0].Error() != `3:5: Alias without a name: "dl"` {
+		t.Errorf("Unexpected errors: %v", errs)
+	}
+}
+
+func TestParseUnitDeclaration(t *testing.T) {
+	session, err := ParseString(`
+    unit kg
+    @ 2020-01-01
+
+    squat:
+      100 5r
+      225 lb 5r
+
+    bench:
+      Unit lb
+      135 5r`)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if session.DefaultUnit != "kg" || len(session.Metadata) != 0 {
+		t.Errorf("Failed to declare session unit: %q %v", session.DefaultUnit, session.Metadata)
+	}
+
+	squat := session.Movements[0].Performances
+	bench := session.Movements[1].Performances
+
+	if squat[0].Unit != "kg" || squat[1].Unit != "lb" || bench[0].Unit != "lb" {
+		t.Errorf("Unexpected units: %q %q %q", squat[0].Unit, squat[1].Unit, bench[0].Unit)
+	}
+}
+
+func TestParsePerSetReps(t *testing.T) {
+	session, err := ParseString(`
+    squat:
+      225 5,5,3r
+      225 R 8,6
+      225 5,,3r`)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	ps := session.Movements[0].Performances
+
+	if len(ps) != 3 {
+		t.Fatalf("Expected 3 performances, got %v", ps)
+	}
+
+	p := ps[0]
+	if !reflect.DeepEqual(p.PerSetReps, []int{5, 5, 3}) || p.Sets != 3 || p.Reps != 5 {
+		t.Errorf("Failed to parse per set reps: %v", p)
+	}
+
+	if v, _ := p.Volume(); v != 225*13 {
+		t.Errorf("Unexpected volume: %v", v)
+	}
+
+	if !reflect.DeepEqual(ps[1].PerSetReps, []int{8, 6}) || ps[1].Sets != 2 || ps[1].Reps != 8 {
+		t.Errorf("Failed to parse leading marker per set reps: %v", ps[1])
+	}
+
+	if len(session.Errors) != 1 || !strings.Contains(session.Errors[0].Error(), "per set reps") {
+		t.Errorf("Expected a malformed list error, got %v", session.Errors)
+	}
+
+	b, err := session.Marshal()
+
+	if err != nil || !strings.Contains(string(b), "  225 5,5,3r\n") {
+		t.Errorf("Per set reps not preserved:\n%s", b)
+	}
+}
+
+func TestParsePerformanceBeforeMovement(t *testing.T) {
+	session, err := ParseString("@ 2020-01-01\n# key: value\n5r\n100 3s\nsquat:\n  200 1r")
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if len(session.Errors) != 3 {
+		t.Fatalf("Expected 3 errors, got %v", session.Errors)
+	}
+
+	if pe := session.Errors[0].(*ParseError); pe.Line != 3 || pe.Msg != `Performance before any movement: "5"` {
+		t.Errorf("Unexpected error: %v", pe)
+	}
+
+	ps := session.Movements[0].Performances
+
+	if len(ps) != 1 || ps[0].Load != 200 || ps[0].Reps != 1 || ps[0].Sets != 1 {
+		t.Errorf("Stray performance leaked into the first movement: %v", ps)
+	}
+}
+
+func BenchmarkParseString(b *testing.B) {
+	bs, err := ioutil.ReadFile("./testdata")
+
+	if err != nil {
+		b.Fatalf("Failed to read: %v", err)
+	}
+	text := string(bs)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseString(text); err != nil {
+			b.Fatalf("Failed to parse: %q", err)
+		}
+	}
+}
+
+func TestParseStringContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	session, err := ParseStringContext(ctx, "squat:\n  100 5r", WithDefaultUnit("kg"))
+
+	if err != nil || session.Movements[0].Performances[0].Unit != "kg" {
+		t.Errorf("Failed to parse with a live context: %v %v", session, err)
+	}
+
+	cancel()
+
+	if _, err := ParseStringContext(ctx, "squat:\n  100 5r"); err != context.Canceled {
+		t.Errorf("Expected the context error, got %v", err)
+	}
+
+	cfg := newParseConfig([]Option{withContext(ctx)})
+	tokens, _ := Tokenize("squat:\n  100 5r")
+
+	if sessions, err := parseTokens(tokens, false, cfg); err != context.Canceled || sessions != nil {
+		t.Errorf("Expected parsing to stop, got %v %v", sessions, err)
+	}
+}
+
+func TestParseConcurrent(t *testing.T) {
+	done := make(chan error)
+
+	for i := 0; i < 8; i++ {
+		go func(i int) {
+			s, err := ParseString(fmt.Sprintf("squat:\n  %d 5r", 100+i))
+			if err == nil && s.Movements[0].Performances[0].Load != float32(100+i) {
+				err = fmt.Errorf("Unexpected session: %v", s)
+			}
+			done <- err
+		}(i)
+	}
+
+	for i := 0; i < 8; i++ {
+		if err := <-done; err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+// largeLog builds a document of n dated sessions, most performances bare.
+func largeLog(n int) []byte {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "@ 2020-01-01\n# unit: kg\n\n")
+		for _, name := range []string{"squat", "bench", "row", "deadlift", "press"} {
+			fmt.Fprintf(&b, "%s:\n", name)
+			for j := 0; j < 5; j++ {
+				fmt.Fprintf(&b, "  %d %dr 3s\n", 100+j*10, 5-j/2)
+			}
+			b.WriteString("    # rpe: 8\n\n")
+		}
+	}
+	return []byte(b.String())
+}
+
+func BenchmarkParseByteLarge(b *testing.B) {
+	text := largeLog(2000)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(text)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseByte(text); err != nil {
+			b.Fatalf("Failed to parse: %q", err)
+		}
+	}
+}
+
+func TestParseMetadataLazily(t *testing.T) {
+	session, err := ParseString("@ 2021-03-04\nsquat:\n  100 5r\n  # rpe: 8\n  120 3r\n")
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	m := session.Movements[0]
+	if m.Metadata != nil || m.Performances[1].Metadata != nil || m.Performances[0].Metadata["rpe"] != "8" {
+		t.Errorf("Expected metadata only where given: %v", m)
+	}
+
+	m.Metadata.Set("k", "v")
+	m.Performances[0].Metadata.Set("k", "v")
+
+	if m.Metadata["k"] != "v" || m.Performances[0].Metadata["k"] != "v" {
+		t.Errorf("Failed to assign metadata: %v", m)
+	}
+}
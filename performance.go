@@ -2,20 +2,47 @@ package traindown
 
 import (
 	"encoding/json"
+	"strings"
+	"time"
 )
 
-// Performance is an expression of a movement.
+// Performance is an expression of a movement. Reps and Sets default to 1, so
+// they are always serialized. A load written as a percentage sets
+// PercentOfMax and PrescribedPercent, leaving Load to be resolved later. A
+// bodyweight performance, written with a load of 0 or with reps alone, has no
+// Load; see Unloaded. Fails are the reps of each set that were attempted but
+// not completed, so they are counted once per set and never exceed Reps in a
+// well formed Performance. A rep range such as "8-12r" sets RepRange, with
+// Reps holding the lower bound. Reps for each set such as "5,5,3r" set
+// PerSetReps, with Sets its length and Reps the most reps in a set. A load
+// relative to bodyweight such as "1.5BW" or "BW+20" is kept in RelativeLoad,
+// with Load resolved from the session bodyweight metadata when present.
+// A load of "same" or '"' repeats the load before it in the same Movement.
+// Parsed performances leave Metadata nil until metadata is given.
 type Performance struct {
-	Fails        int     `json:"fails"`
-	Load         float32 `json:"load"`
-	PercentOfMax float32 `json:"percentOfMax,omitempty"`
-	Reps         int     `json:"reps"`
-	Sequence     int     `json:"sequence"`
-	Sets         int     `json:"sets"`
-	Unit         string  `json:"unit"`
+	Fails             int             `json:"fails,omitempty"`
+	Load              float32         `json:"load"`
+	LoadMin           float32         `json:"loadMin,omitempty"`        // a range such as "225-245", else Load
+	LoadMax           float32         `json:"loadMax,omitempty"`        // a range such as "225-245", else Load
+	LoadComponents    []LoadComponent `json:"loadComponents,omitempty"` // "100kg + 20kg collars", summed into Load
+	LoadModifiers     []LoadModifier  `json:"loadModifiers,omitempty"`  // "+40band", not in Load; see EffectiveLoad
+	PercentOfMax      float32         `json:"percentOfMax,omitempty"`
+	PrescribedPercent bool            `json:"prescribedPercent,omitempty"`
+	Reps              int             `json:"reps"`
+	PerSetReps        []int           `json:"perSetReps,omitempty"`
+	RelativeLoad      string          `json:"relativeLoad,omitempty"`
+	RepRange          *RepRange       `json:"repRange,omitempty"`
+	Rest              time.Duration   `json:"rest,omitempty"`
+	RPE               float32         `json:"rpe,omitempty"`
+	Sequence          int             `json:"sequence"`
+	Sets              int             `json:"sets"`
+	SetDetails        []*Set          `json:"setDetails,omitempty"` // the sets written out one by one
+	Span              *SourceSpan     `json:"span,omitempty"`
+	Tempo             *Tempo          `json:"tempo,omitempty"`
+	Unit              string          `json:"unit"`
 
-	Metadata Metadata `json:"metadata"`
-	Notes    []string `json:"notes"`
+	Metadata Metadata `json:"metadata,omitempty"`
+	Notes    []string `json:"notes,omitempty"`
 }
 
 /* Public */
@@ -36,8 +63,125 @@ func (p Performance) String() string {
 	return string(ps)
 }
 
-// Volume produces a float and a string containing the unit.
+// Formulas accepted by EstimatedOneRepMax.
+const (
+	Brzycki = "brzycki"
+	Epley   = "epley"
+)
+
+// ConvertTo returns a copy of the Performance with its Load converted to unit.
+func (p Performance) ConvertTo(unit string) (*Performance, error) {
+	if _, err := ConvertLoad(p.Load, p.Unit, unit); err != nil {
+		return nil, err
+	}
+
+	c := p.Clone()
+	c.convertLoads(unit)
+	return c, nil
+}
+
+// EstimatedOneRepMax estimates the one rep max from the Load and Reps using
+// the named formula. A single rep is the load itself, and zero reps or an
+// unknown formula yield zero. Brzycki is undefined from 37 reps and up, which
+// also yields zero.
+func (p Performance) EstimatedOneRepMax(formula string) float32 {
+	if p.Reps <= 0 {
+		return 0
+	}
+
+	if p.Reps == 1 {
+		return p.Load
+	}
+
+	r := float32(p.Reps)
+
+	switch formula {
+	case Brzycki:
+		if p.Reps >= 37 {
+			return 0
+		}
+		return p.Load * 36 / (37 - r)
+	case Epley:
+		return p.Load * (1 + r/30)
+	}
+
+	return 0
+}
+
+// GrossVolume is load times reps times sets, ignoring any failed reps.
+func (p Performance) GrossVolume() float32 {
+	if p.PerSetReps != nil {
+		return float32(sumReps(p.PerSetReps)) * p.Load
+	}
+	return float32(p.Reps) * float32(p.Sets) * p.Load
+}
+
+// MaxReps is the upper bound of the RepRange, or Reps when no range was given.
+func (p Performance) MaxReps() int {
+	if p.RepRange != nil {
+		return p.RepRange.Max
+	}
+	return p.Reps
+}
+
+// NotesText joins the Performance notes with sep, skipping blank notes.
+func (p Performance) NotesText(sep string) string {
+	return joinNotes(p.Notes, sep)
+}
+
+// SuccessfulEstimatedOneRepMax is EstimatedOneRepMax using SuccessfulReps, so
+// failed reps do not inflate the estimate.
+func (p Performance) SuccessfulEstimatedOneRepMax(formula string) float32 {
+	p.Reps = p.SuccessfulReps()
+	return p.EstimatedOneRepMax(formula)
+}
+
+// SuccessfulReps is the reps completed in each set, Reps less Fails, and never
+// less than 0.
+func (p Performance) SuccessfulReps() int {
+	if p.Fails > p.Reps {
+		return 0
+	}
+	return p.Reps - p.Fails
+}
+
+// TotalReps is the reps of every set, as written, so "225 5r 3s" is 15. Reps
+// alone count a single set. Failed reps are included; see SuccessfulReps.
+func (p Performance) TotalReps() int {
+	if len(p.SetDetails) > 0 {
+		var reps int
+		for _, s := range p.SetDetails {
+			reps += s.Reps
+		}
+		return reps
+	}
+	if p.PerSetReps != nil {
+		return sumReps(p.PerSetReps)
+	}
+	return p.Reps * p.Sets
+}
+
+// Unloaded reports whether the Performance carries no external load, as with
+// bodyweight movements.
+func (p Performance) Unloaded() bool {
+	return p.Load == 0 && !p.PrescribedPercent
+}
+
+// Volume produces a float and a string containing the unit. Fails are counted
+// per set and subtracted from the reps. With SetDetails the fails of each Set
+// are used instead.
 func (p Performance) Volume() (float32, string) {
+	if len(p.SetDetails) > 0 {
+		var reps int
+		for _, s := range p.SetDetails {
+			reps += s.Reps - s.Fails
+		}
+		return float32(reps) * p.Load, p.Unit
+	}
+	if p.PerSetReps != nil {
+		reps := sumReps(p.PerSetReps) - p.Fails*len(p.PerSetReps)
+		return float32(reps) * p.Load, p.Unit
+	}
 	v := (float32(p.Reps) - float32(p.Fails)) * float32(p.Sets) * p.Load
 	return v, p.Unit
 }
@@ -52,8 +196,101 @@ func (p *Performance) assignSpecial(k string, v string) bool {
 	return false
 }
 
+// assignTyped promotes well known metadata into typed fields. The metadata
+// itself is left in place.
+func (p *Performance) assignTyped(k string, v string) error {
+	switch strings.ToLower(k) {
+	case "rest":
+		d, err := durationValue(v, "rest")
+		if err != nil {
+			return err
+		}
+		p.Rest = d
+	case "rpe":
+		f, err := floatValue(v, "rpe")
+		if err != nil {
+			return err
+		}
+		p.RPE = f
+	case "tempo":
+		t, err := ParseTempo(v)
+		if err != nil {
+			return err
+		}
+		p.Tempo = &t
+	}
+	return nil
+}
+
+// convertLoads converts the Load, LoadMin, LoadMax, and LoadComponents to
+// unit, which the Unit must be convertible to.
+func (p *Performance) convertLoads(unit string) {
+	p.Load, _ = ConvertLoad(p.Load, p.Unit, unit)
+
+	if p.LoadMin != 0 || p.LoadMax != 0 {
+		p.LoadMin, _ = ConvertLoad(p.LoadMin, p.Unit, unit)
+		p.LoadMax, _ = ConvertLoad(p.LoadMax, p.Unit, unit)
+	}
+
+	for i, lc := range p.LoadComponents {
+		from := lc.Unit
+		if from == "" {
+			from = p.Unit
+		}
+		if l, err := ConvertLoad(lc.Load, from, unit); err == nil {
+			p.LoadComponents[i].Load, p.LoadComponents[i].Unit = l, unit
+		}
+	}
+
+	p.Unit = unit
+}
+
+// setLoad sets the Load along with a LoadMin and LoadMax of the same.
+func (p *Performance) setLoad(l float32) {
+	p.Load, p.LoadMin, p.LoadMax = l, l, l
+}
+
+// bounds is LoadMin and LoadMax, taken to be Load when neither is set, as
+// for a Performance built by hand.
+func (p *Performance) bounds() (float32, float32) {
+	if p.LoadMin == 0 && p.LoadMax == 0 {
+		return p.Load, p.Load
+	}
+	return p.LoadMin, p.LoadMax
+}
+
+// copyLoad gives p the load of other, for a performance written as "same".
+func (p *Performance) copyLoad(other *Performance) {
+	p.Load = other.Load
+	p.LoadMin = other.LoadMin
+	p.LoadMax = other.LoadMax
+	p.PercentOfMax = other.PercentOfMax
+	p.PrescribedPercent = other.PrescribedPercent
+	p.RelativeLoad = other.RelativeLoad
+	p.Unit = other.Unit
+
+	if other.LoadModifiers != nil {
+		p.LoadModifiers = append([]LoadModifier{}, other.LoadModifiers...)
+	}
+}
+
+// newPerformance is NewPerformance without the Metadata map, which the parser
+// allocates only once metadata is given.
+func newPerformance() *Performance {
+	return &Performance{
+		Notes: make([]string, 0),
+		Reps:  1,
+		Sets:  1,
+		Unit:  "unknown unit",
+	}
+}
+
+func (p Performance) hasUnit() bool {
+	return p.Unit != "unknown unit" && p.Unit != ""
+}
+
 func (p *Performance) maybeInheritUnit(s *Session, m *Movement) {
-	if p.Unit == "unknown unit" || p.Unit == "" {
+	if !p.hasUnit() {
 		if s.DefaultUnit != "" {
 			p.Unit = s.DefaultUnit
 		}
@@ -63,3 +300,11 @@ func (p *Performance) maybeInheritUnit(s *Session, m *Movement) {
 		}
 	}
 }
+
+func sumReps(reps []int) int {
+	var total int
+	for _, r := range reps {
+		total += r
+	}
+	return total
+}
@@ -113,3 +113,125 @@ func TestMaybeInheritUnit(t *testing.T) {
 		t.Errorf("Incorrectly overwrote a set unit")
 	}
 }
+
+func TestGrossVolume(t *testing.T) {
+	p := Performance{Fails: 2, Load: 100, Reps: 5, Sets: 3}
+
+	if v := p.GrossVolume(); v != 1500 {
+		t.Errorf("Unexpected gross volume: %v", v)
+	}
+
+	if v, _ := p.Volume(); v != 900 {
+		t.Errorf("Unexpected volume: %v", v)
+	}
+}
+
+func TestEstimatedOneRepMax(t *testing.T) {
+	cases := []struct {
+		reps    int
+		formula string
+		want    float32
+	}{
+		{0, Epley, 0},
+		{1, Epley, 300},
+		{1, Brzycki, 300},
+		{10, Epley, 400},
+		{10, Brzycki, 400},
+		{37, Brzycki, 0},
+		{5, "your mom", 0},
+	}
+
+	for _, c := range cases {
+		p := Performance{Load: 300, Reps: c.reps}
+
+		if got := p.EstimatedOneRepMax(c.formula); got != c.want {
+			t.Errorf("Unexpected estimate for %d reps with %q: %v", c.reps, c.formula, got)
+		}
+	}
+}
+
+func TestAssignTypedToPerformance(t *testing.T) {
+	p := NewPerformance()
+
+	if err := p.assignTyped("your", "mom"); err != nil || p.RPE != 0 {
+		t.Errorf("Invalid assignment of typed field")
+	}
+
+	for _, k := range []string{"rpe", "RPE", "Rpe"} {
+		p.RPE = 0
+
+		if err := p.assignTyped(k, "8.5"); err != nil || p.RPE != 8.5 {
+			t.Errorf("Failed to assign RPE for %q", k)
+		}
+	}
+
+	if err := p.assignTyped("rpe", "hard"); err == nil {
+		t.Errorf("Expected an error for invalid RPE")
+	}
+}
+
+func TestConvertTo(t *testing.T) {
+	p := &Performance{Load: 100, Unit: "kg", Metadata: Metadata{"your": "mom"}}
+
+	c, err := p.ConvertTo("lb")
+
+	if err != nil || c.Load != 220.462 || c.Unit != "lb" {
+		t.Errorf("Failed to convert: %v (%v)", c, err)
+	}
+
+	c.Metadata["your"] = "dad"
+	if p.Load != 100 || p.Unit != "kg" || p.Metadata["your"] != "mom" {
+		t.Errorf("Conversion modified the original: %v", p)
+	}
+
+	c, err = p.ConvertTo("kg")
+
+	if err != nil || c.Load != 100 || c.Unit != "kg" {
+		t.Errorf("Conversion to the same unit changed the load: %v (%v)", c, err)
+	}
+
+	if _, err = p.ConvertTo("stone"); err == nil {
+		t.Errorf("Expected an error for an unknown unit")
+	}
+}
+
+func TestUnloaded(t *testing.T) {
+	if !(Performance{Reps: 10}).Unloaded() {
+		t.Error("Expected a zero load to be unloaded")
+	}
+
+	if (Performance{Load: 100}).Unloaded() || (Performance{PercentOfMax: 80, PrescribedPercent: true}).Unloaded() {
+		t.Error("Expected loaded performances")
+	}
+}
+
+func TestSuccessfulReps(t *testing.T) {
+	cases := []struct {
+		reps     int
+		fails    int
+		expected int
+	}{
+		{5, 0, 5},
+		{5, 2, 3},
+		{5, 5, 0},
+		{5, 7, 0},
+	}
+
+	for _, c := range cases {
+		p := Performance{Load: 100, Reps: c.reps, Fails: c.fails, Sets: 3}
+
+		if got := p.SuccessfulReps(); got != c.expected {
+			t.Errorf("Expected %d successful reps from %d with %d fails, got %d", c.expected, c.reps, c.fails, got)
+		}
+	}
+
+	p := Performance{Load: 100, Reps: 5, Fails: 4}
+
+	if e := p.SuccessfulEstimatedOneRepMax(Epley); e != 100 {
+		t.Errorf("Expected the load for a single successful rep, got %v", e)
+	}
+
+	if e := (Performance{Load: 100, Reps: 3, Fails: 5}).SuccessfulEstimatedOneRepMax(Epley); e != 0 {
+		t.Errorf("Expected no estimate without a successful rep, got %v", e)
+	}
+}
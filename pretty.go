@@ -0,0 +1,104 @@
+package traindown
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Pretty renders the Session as plain text for reading in a terminal. Unlike
+// Marshal the output is not Traindown and is not meant to be parsed. Each
+// Performance reads as load × reps × sets, with loads right aligned within
+// their Movement.
+func (s *Session) Pretty() string {
+	var b strings.Builder
+
+	var heading []string
+	if !s.Date.IsZero() {
+		heading = append(heading, formatDate(s.Date))
+	}
+	if s.Title != "" {
+		heading = append(heading, s.Title)
+	}
+	if len(heading) > 0 {
+		b.WriteString(strings.Join(heading, " "))
+		b.WriteString("\n")
+	}
+
+	writePrettyDetails(&b, "", s.Metadata, s.Notes)
+
+	for _, m := range s.Movements {
+		b.WriteString("\n")
+		if m.SuperSet {
+			b.WriteString("+ ")
+		}
+		b.WriteString(m.Name)
+		b.WriteString("\n")
+
+		writePrettyDetails(&b, "  ", m.Metadata, m.Notes)
+
+		loads := make([]string, len(m.Performances))
+		width := 0
+		for i, p := range m.Performances {
+			loads[i] = prettyLoad(p)
+			if len(loads[i]) > width {
+				width = len(loads[i])
+			}
+		}
+
+		for i, p := range m.Performances {
+			fmt.Fprintf(&b, "  %*s × %d × %d", width, loads[i], p.Reps, p.Sets)
+			if p.Fails != 0 {
+				fmt.Fprintf(&b, " (%d failed)", p.Fails)
+			}
+			b.WriteString("\n")
+
+			writePrettyDetails(&b, "    ", p.Metadata, p.Notes)
+		}
+	}
+
+	return b.String()
+}
+
+/* Private */
+
+func prettyLoad(p *Performance) string {
+	if p.PrescribedPercent {
+		return formatLoad(p.PercentOfMax) + "%"
+	}
+
+	if p.RelativeLoad != "" {
+		return p.RelativeLoad
+	}
+
+	if p.Unloaded() {
+		return "BW"
+	}
+
+	l := formatLoad(p.Load)
+	if p.LoadMax > p.LoadMin {
+		l = formatLoad(p.LoadMin) + "-" + formatLoad(p.LoadMax)
+	}
+
+	if p.hasUnit() {
+		return l + " " + p.Unit
+	}
+
+	return l
+}
+
+func writePrettyDetails(b *strings.Builder, indent string, md Metadata, notes []string) {
+	keys := make([]string, 0, len(md))
+	for k := range md {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fmt.Fprintf(b, "%s%s: %v\n", indent, k, md[k])
+	}
+
+	for _, n := range notes {
+		fmt.Fprintf(b, "%s* %s\n", indent, n)
+	}
+}
@@ -0,0 +1,45 @@
+package traindown
+
+import (
+	"testing"
+)
+
+func TestPretty(t *testing.T) {
+	session, err := ParseString(`
+    @ 2020-01-01
+    # title: Heavy day
+    # unit: lb
+    * session note
+
+    squat:
+      95 5r
+      225 5r 3s
+        * felt good
+      315 1r 1f
+
+    + pullups:
+      # grip: wide
+      10r`)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	expected := `2020-01-01 Heavy day
+* session note
+
+squat
+   95 lb × 5 × 1
+  225 lb × 5 × 3
+    * felt good
+  315 lb × 1 × 1 (1 failed)
+
++ pullups
+  grip: wide
+  BW × 10 × 1
+`
+
+	if got := session.Pretty(); got != expected {
+		t.Errorf("Pretty mismatch.\n\nGot:\n%s\n\nExpected:\n%s", got, expected)
+	}
+}
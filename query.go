@@ -0,0 +1,62 @@
+package traindown
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// FilterByDateRange returns the sessions dated between from and to, inclusive
+// on both ends, in their original order. A zero from or to leaves that end of
+// the range open.
+func FilterByDateRange(sessions []*Session, from time.Time, to time.Time) []*Session {
+	var filtered []*Session
+	for _, s := range sessions {
+		if !from.IsZero() && s.Date.Before(from) {
+			continue
+		}
+		if !to.IsZero() && s.Date.After(to) {
+			continue
+		}
+		filtered = append(filtered, s)
+	}
+	return filtered
+}
+
+// SortByDate orders sessions oldest first. The sort is stable, so sessions
+// sharing a date keep their order.
+func SortByDate(sessions []*Session) {
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].Date.Before(sessions[j].Date)
+	})
+}
+
+// MonthlyVolume sums the Volume of sessions by calendar month, keyed as
+// "2006-01". Units are summed together, as by Session.Volume.
+func MonthlyVolume(sessions []*Session) map[string]float32 {
+	return bucketVolume(sessions, func(d time.Time) string {
+		return d.Format("2006-01")
+	})
+}
+
+// WeeklyVolume sums the Volume of sessions by ISO 8601 week, keyed as
+// "2006-W01". Weeks start on Monday and belong to the year holding their
+// Thursday, so the first days of January may fall in the last week of the
+// year before. Units are summed together, as by Session.Volume.
+func WeeklyVolume(sessions []*Session) map[string]float32 {
+	return bucketVolume(sessions, func(d time.Time) string {
+		y, w := d.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", y, w)
+	})
+}
+
+/* Private */
+
+func bucketVolume(sessions []*Session, key func(time.Time) string) map[string]float32 {
+	v := make(map[string]float32)
+	for _, s := range sessions {
+		sv, _ := s.Volume()
+		v[key(s.Date)] += sv
+	}
+	return v
+}
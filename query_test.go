@@ -0,0 +1,94 @@
+package traindown
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFilterByDateRange(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2020, 1, d, 0, 0, 0, 0, time.UTC) }
+
+	var sessions []*Session
+	for _, d := range []int{3, 1, 4, 2, 5} {
+		s := NewSession()
+		s.Date = day(d)
+		sessions = append(sessions, s)
+	}
+
+	cases := []struct {
+		from     time.Time
+		to       time.Time
+		expected []int
+	}{
+		{day(2), day(4), []int{3, 4, 2}},
+		{time.Time{}, day(2), []int{1, 2}},
+		{day(4), time.Time{}, []int{4, 5}},
+		{time.Time{}, time.Time{}, []int{3, 1, 4, 2, 5}},
+		{day(6), day(7), []int{}},
+	}
+
+	for _, c := range cases {
+		got := FilterByDateRange(sessions, c.from, c.to)
+
+		if len(got) != len(c.expected) {
+			t.Errorf("Expected %d sessions from %v to %v, got %d", len(c.expected), c.from, c.to, len(got))
+			continue
+		}
+
+		for i, d := range c.expected {
+			if got[i].Date != day(d) {
+				t.Errorf("Expected %v at %d, got %v", day(d), i, got[i].Date)
+			}
+		}
+	}
+
+	SortByDate(sessions)
+
+	for i, s := range sessions {
+		if s.Date != day(i+1) {
+			t.Errorf("Expected %v at %d, got %v", day(i+1), i, s.Date)
+		}
+	}
+}
+
+func TestWeeklyAndMonthlyVolume(t *testing.T) {
+	sessions, err := ParseSessions(`
+    @ 2020-12-27
+    squat:
+      100 1r
+
+    @ 2020-12-28
+    squat:
+      100 2r
+
+    @ 2021-01-03
+    squat:
+      100 3r
+
+    @ 2021-01-04
+    squat:
+      100 4r`)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	weekly := WeeklyVolume(sessions)
+	expected := map[string]float32{"2020-W52": 100, "2020-W53": 500, "2021-W01": 400}
+
+	if len(weekly) != len(expected) {
+		t.Errorf("Expected %v, got %v", expected, weekly)
+	}
+
+	for k, v := range expected {
+		if weekly[k] != v {
+			t.Errorf("Expected %v for %s, got %v", v, k, weekly[k])
+		}
+	}
+
+	monthly := MonthlyVolume(sessions)
+
+	if len(monthly) != 2 || monthly["2020-12"] != 300 || monthly["2021-01"] != 700 {
+		t.Errorf("Unexpected monthly volume: %v", monthly)
+	}
+}
@@ -0,0 +1,44 @@
+package traindown
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RepRange is a prescribed range of reps, as in "8-12r".
+type RepRange struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// ParseRepRange reads a range written as "8-12". A single number is a range
+// of one. Inverted ranges such as "12-8" are an error.
+func ParseRepRange(s string) (RepRange, error) {
+	parts := strings.SplitN(strings.TrimSpace(s), "-", 2)
+
+	min, err := intValue(strings.TrimSpace(parts[0]), "reps")
+
+	if err != nil {
+		return RepRange{}, err
+	}
+
+	if len(parts) == 1 {
+		return RepRange{min, min}, nil
+	}
+
+	max, err := intValue(strings.TrimSpace(parts[1]), "reps")
+
+	if err != nil {
+		return RepRange{}, err
+	}
+
+	if max < min {
+		return RepRange{}, fmt.Errorf("Inverted rep range: %q", s)
+	}
+
+	return RepRange{min, max}, nil
+}
+
+func (r RepRange) String() string {
+	return fmt.Sprintf("%d-%d", r.Min, r.Max)
+}
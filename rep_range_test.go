@@ -0,0 +1,31 @@
+package traindown
+
+import (
+	"testing"
+)
+
+func TestParseRepRange(t *testing.T) {
+	cases := map[string]RepRange{
+		"8-12": RepRange{8, 12},
+		" 5 ":  RepRange{5, 5},
+		"6-6":  RepRange{6, 6},
+	}
+
+	for s, expected := range cases {
+		r, err := ParseRepRange(s)
+
+		if err != nil {
+			t.Errorf("Failed to parse %q: %q", s, err)
+		}
+
+		if r != expected {
+			t.Errorf("Expected %v from %q, got %v", expected, s, r)
+		}
+	}
+
+	for _, s := range []string{"12-8", "x", "8-", ""} {
+		if _, err := ParseRepRange(s); err == nil {
+			t.Errorf("Expected an error for %q", s)
+		}
+	}
+}
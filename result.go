@@ -0,0 +1,58 @@
+package traindown
+
+import "errors"
+
+// ParseResult is a parsed Session with its Errors split by severity. Errors
+// are hard failures, where part of the input could not be understood and was
+// skipped or left at its default, while Warnings are recoverable; see
+// ParseError. Both keep the order in which they were recorded, and the
+// Session still holds them all in its own Errors.
+type ParseResult struct {
+	Session  *Session
+	Errors   []error
+	Warnings []error
+}
+
+/* Public */
+
+// ParseByteResult is ParseByte, configured by opts, returning a ParseResult.
+// The error is reserved for input that could not be parsed at all, or for a
+// ParseErrors error when strict. ErrEmptyInput is returned alongside a result
+// for an empty Session.
+func ParseByteResult(txt []byte, opts ...Option) (*ParseResult, error) {
+	s, err := parse("", txt, opts)
+
+	if err != nil {
+		if _, ok := err.(ParseErrors); !ok && err != ErrEmptyInput {
+			return nil, err
+		}
+	}
+
+	return NewParseResult(s), err
+}
+
+// NewParseResult splits the Errors of s into a ParseResult.
+func NewParseResult(s *Session) *ParseResult {
+	r := &ParseResult{Session: s}
+
+	for _, err := range s.Errors {
+		if IsWarning(err) {
+			r.Warnings = append(r.Warnings, err)
+		} else {
+			r.Errors = append(r.Errors, err)
+		}
+	}
+
+	return r
+}
+
+// IsWarning reports whether err is a ParseError marked as a Warning.
+func IsWarning(err error) bool {
+	var pe *ParseError
+	return errors.As(err, &pe) && pe.Warning
+}
+
+// OK reports whether the Session parsed without hard failures.
+func (r *ParseResult) OK() bool {
+	return len(r.Errors) == 0
+}
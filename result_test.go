@@ -0,0 +1,48 @@
+package traindown
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParseByteResult(t *testing.T) {
+	r, err := ParseByteResult([]byte(`
+    @ not a date
+    # rpe: 8
+    # rpe: 9
+
+    squat:
+      110% 5r
+      100 5.5r
+      100 5r +5sled`), WithDuplicateKeyWarnings(true), WithPercentValidation(true))
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if len(r.Session.Errors) != 5 {
+		t.Fatalf("Expected 5 errors on the session, got %v", r.Session.Errors)
+	}
+
+	if len(r.Warnings) != 3 || !IsWarning(r.Warnings[0]) || r.Warnings[1].(*ParseError).Line != 4 {
+		t.Errorf("Unexpected warnings: %v", r.Warnings)
+	}
+
+	if len(r.Errors) != 2 || IsWarning(r.Errors[0]) || r.OK() {
+		t.Errorf("Unexpected errors: %v", r.Errors)
+	}
+
+	r, err = ParseByteResult([]byte("@ 2020-01-01\n\nsquat:\n  100 5r"))
+
+	if err != nil || !r.OK() || len(r.Warnings) != 0 || r.Session.Movements[0].Name != "squat" {
+		t.Errorf("Unexpected result: %v %v", r, err)
+	}
+
+	if r, err = ParseByteResult([]byte("  ")); err != ErrEmptyInput || r == nil || !r.OK() {
+		t.Errorf("Expected an empty result: %v %v", r, err)
+	}
+
+	if IsWarning(fmt.Errorf("plain")) {
+		t.Error("Expected a plain error not to be a warning")
+	}
+}
@@ -2,18 +2,27 @@ package traindown
 
 import (
 	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
 	"time"
 )
 
-// Session is a collection of Movements that occurred.
+// Session is a collection of Movements that occurred. DateLayout holds the
+// layout given by WithDateLayout when it was used to read Date, and is empty
+// when the date was guessed. Title comes from a session level "title"
+// metadata key.
 type Session struct {
 	Date        time.Time   `json:"date"`
+	DateLayout  string      `json:"-"`
 	DefaultUnit string      `json:"defaultUnit,omitempty"`
-	Errors      []error     `json:"errors"`
+	Errors      []error     `json:"errors,omitempty"`
 	Movements   []*Movement `json:"movements"`
+	Title       string      `json:"title,omitempty"`
 
-	Metadata Metadata `json:"metadata"`
-	Notes    []string `json:"notes"`
+	Metadata Metadata `json:"metadata,omitempty"`
+	Notes    []string `json:"notes,omitempty"`
 }
 
 /* Public */
@@ -32,6 +41,291 @@ func (s Session) String() string {
 	return string(ss)
 }
 
+// AverageIntensity returns the average PercentOfMax of the Session weighted
+// by reps times sets, with percentages as ComputePercentages would set them,
+// though the Session is left unchanged. Failed reps still count toward the
+// weighting since they were attempted at the load. Performances without a
+// load or a prescribed percent are left out, and a Session with nothing to
+// average returns 0.
+func (s *Session) AverageIntensity() float32 {
+	var total float32
+	var reps int
+	for _, m := range s.Movements {
+		max := m.maxLoad()
+		for _, p := range m.Performances {
+			if p.Load == 0 && !p.PrescribedPercent {
+				continue
+			}
+			total += percentOfMax(p, max) * float32(p.Reps*p.Sets)
+			reps += p.Reps * p.Sets
+		}
+	}
+
+	if reps == 0 {
+		return 0
+	}
+
+	return total / float32(reps)
+}
+
+// EachPerformance calls fn with every Performance in the Session, in order,
+// along with the Movement it belongs to.
+func (s *Session) EachPerformance(fn func(m *Movement, p *Performance)) {
+	for _, m := range s.Movements {
+		for _, p := range m.Performances {
+			fn(m, p)
+		}
+	}
+}
+
+// Walk visits the Session, then each Movement followed by its performances,
+// and then each Performance's sets, in order. The visitor is given a *Session,
+// *Movement, *Performance, or *Set; returning false skips that node's
+// children.
+func (s *Session) Walk(visitor func(node interface{}) bool) {
+	if !visitor(s) {
+		return
+	}
+
+	for _, m := range s.Movements {
+		if !visitor(m) {
+			continue
+		}
+
+		for _, p := range m.Performances {
+			if !visitor(p) {
+				continue
+			}
+
+			for _, set := range p.SetDetails {
+				visitor(set)
+			}
+		}
+	}
+}
+
+// FindMovement returns the first Movement whose name matches, ignoring case
+// and surrounding whitespace.
+func (s Session) FindMovement(name string) (*Movement, bool) {
+	for _, m := range s.Movements {
+		if sameName(m.Name, name) {
+			return m, true
+		}
+	}
+	return nil, false
+}
+
+// MovementsByName returns every Movement whose name matches, ignoring case
+// and surrounding whitespace.
+func (s Session) MovementsByName(name string) []*Movement {
+	var ms []*Movement
+	for _, m := range s.Movements {
+		if sameName(m.Name, name) {
+			ms = append(ms, m)
+		}
+	}
+	return ms
+}
+
+// MovementsByMuscle returns every Movement tagged with the muscle group,
+// ignoring case and surrounding whitespace. See Movement.Muscles.
+func (s Session) MovementsByMuscle(muscle string) []*Movement {
+	var ms []*Movement
+	for _, m := range s.Movements {
+		for _, mm := range m.Muscles() {
+			if sameName(mm, muscle) {
+				ms = append(ms, m)
+				break
+			}
+		}
+	}
+	return ms
+}
+
+// MovementsMatching returns every Movement whose name contains substr,
+// ignoring case and surrounding whitespace.
+func (s Session) MovementsMatching(substr string) []*Movement {
+	needle := strings.ToLower(strings.TrimSpace(substr))
+
+	var ms []*Movement
+	for _, m := range s.Movements {
+		if strings.Contains(strings.ToLower(m.Name), needle) {
+			ms = append(ms, m)
+		}
+	}
+	return ms
+}
+
+// NormalizeUnits converts every Performance to unit. Performances without a
+// unit are left alone. If any unit cannot be converted an error is returned
+// and nothing is changed.
+func (s *Session) NormalizeUnits(unit string) error {
+	if _, ok := CanonicalUnit(unit); !ok {
+		return fmt.Errorf("Unknown unit: %q", unit)
+	}
+
+	for _, m := range s.Movements {
+		for _, p := range m.Performances {
+			if !p.hasUnit() {
+				continue
+			}
+			if _, ok := CanonicalUnit(p.Unit); !ok {
+				return fmt.Errorf("Unknown unit: %q", p.Unit)
+			}
+		}
+	}
+
+	for _, m := range s.Movements {
+		for _, p := range m.Performances {
+			if !p.hasUnit() {
+				continue
+			}
+			p.convertLoads(unit)
+		}
+	}
+
+	return nil
+}
+
+// NotesText joins the Session notes with sep, skipping blank notes.
+func (s Session) NotesText(sep string) string {
+	return joinNotes(s.Notes, sep)
+}
+
+// ResolvePercents sets the Load of each Performance given as a percentage but
+// without a Load from the max of its Movement, matched by name ignoring case
+// and surrounding whitespace. An error is appended to Errors for each such
+// Movement missing from maxes.
+func (s *Session) ResolvePercents(maxes map[string]float32) {
+	for _, m := range s.Movements {
+		max, found := maxes[m.Name]
+		if !found {
+			for name, v := range maxes {
+				if sameName(name, m.Name) {
+					max, found = v, true
+					break
+				}
+			}
+		}
+
+		reported := false
+		for _, p := range m.Performances {
+			if p.PercentOfMax == 0 || p.Load != 0 {
+				continue
+			}
+
+			if !found {
+				if !reported {
+					s.Errors = append(s.Errors, fmt.Errorf("No max for %q", m.Name))
+					reported = true
+				}
+				continue
+			}
+
+			p.setLoad(max * p.PercentOfMax / 100)
+		}
+	}
+}
+
+// RoundLoads rounds the Load, LoadMin, and LoadMax of every Performance to
+// decimals places, cleaning up float32 artifacts such as 225.00001 left by
+// unit conversion.
+func (s *Session) RoundLoads(decimals int) {
+	pow := math.Pow10(decimals)
+	round := func(l float32) float32 {
+		return float32(math.Round(float64(l)*pow) / pow)
+	}
+	s.EachPerformance(func(m *Movement, p *Performance) {
+		p.Load = round(p.Load)
+		p.LoadMin = round(p.LoadMin)
+		p.LoadMax = round(p.LoadMax)
+	})
+}
+
+// Sort orders the Movements, and each Movement's Performances, by Sequence.
+// The sort is stable, so data without sequences keeps its input order.
+func (s *Session) Sort() {
+	sort.SliceStable(s.Movements, func(i, j int) bool {
+		return s.Movements[i].Sequence < s.Movements[j].Sequence
+	})
+
+	for _, m := range s.Movements {
+		ps := m.Performances
+		sort.SliceStable(ps, func(i, j int) bool {
+			return ps[i].Sequence < ps[j].Sequence
+		})
+	}
+}
+
+// SuperSetGroups groups the Movements into the blocks they were performed in.
+// A Movement marked SuperSet joins the group of the Movement before it, so the
+// first Movement of a superset is the unmarked one preceding the marked ones.
+// A standalone Movement is a group of one.
+func (s Session) SuperSetGroups() [][]*Movement {
+	var groups [][]*Movement
+	for _, m := range s.Movements {
+		if m.SuperSet && len(groups) > 0 {
+			groups[len(groups)-1] = append(groups[len(groups)-1], m)
+			continue
+		}
+		groups = append(groups, []*Movement{m})
+	}
+	return groups
+}
+
+// SuperSetGroupsByTag groups the Movements by their "ss" or "superset"
+// metadata, such as "# ss: A", whether or not they are adjacent. A Movement
+// marked SuperSet without a tag of its own shares the tag of the Movement
+// before it. Movements without a tag are left out, and each group keeps the
+// order of the Session.
+func (s Session) SuperSetGroupsByTag() map[string][]*Movement {
+	groups := make(map[string][]*Movement)
+
+	var tag string
+	for _, m := range s.Movements {
+		if k, ok := m.Metadata.lookup("ss", "superset"); ok {
+			v, _ := m.Metadata.String(k)
+			tag = strings.TrimSpace(v)
+		} else if !m.SuperSet {
+			tag = ""
+		}
+
+		if tag != "" {
+			groups[tag] = append(groups[tag], m)
+		}
+	}
+
+	return groups
+}
+
+// TotalReps is the reps of every set of the Session, multiplying the Reps of
+// each Performance by its Sets as in Performance.TotalReps, not the sum of
+// Reps alone.
+func (s *Session) TotalReps() int {
+	var reps int
+	s.EachPerformance(func(m *Movement, p *Performance) {
+		reps += p.TotalReps()
+	})
+	return reps
+}
+
+// TotalSets is the Sets of every Performance of the Session, so "225 5r 3s"
+// counts as three sets and a line without a set count as one.
+func (s *Session) TotalSets() int {
+	var sets int
+	s.EachPerformance(func(m *Movement, p *Performance) {
+		sets += p.Sets
+	})
+	return sets
+}
+
+// Volume computes the total volume of the Session regardless of unit. If the
+// performances use more than one unit the total is still returned along with
+// a *MixedUnitsError; use Volumes for a per unit breakdown.
+func (s Session) Volume() (float32, error) {
+	return volumeTotal(s.Volumes())
+}
+
 // Volumes computes the volume performed by unit.
 func (s Session) Volumes() map[string]float32 {
 	v := make(map[string]float32)
@@ -51,12 +345,54 @@ func (s Session) Volumes() map[string]float32 {
 	return v
 }
 
+// ComputePercentages computes PercentOfMax for every Movement in the Session.
+func (s *Session) ComputePercentages() {
+	for _, m := range s.Movements {
+		m.ComputePercentages()
+	}
+}
+
+// Unit is the unit the Session is logged in: its DefaultUnit, or else its
+// "unit" or "u" metadata in any casing.
+func (s *Session) Unit() (string, bool) {
+	if s.DefaultUnit != "" {
+		return s.DefaultUnit, true
+	}
+
+	k, ok := s.Metadata.lookup("unit", "u")
+	if !ok {
+		return "", false
+	}
+
+	v, _ := s.Metadata.String(k)
+	v = strings.TrimSpace(v)
+	return v, v != ""
+}
+
 /* Private */
 
+func joinNotes(notes []string, sep string) string {
+	var kept []string
+	for _, n := range notes {
+		if strings.TrimSpace(n) != "" {
+			kept = append(kept, n)
+		}
+	}
+	return strings.Join(kept, sep)
+}
+
+func sameName(a string, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
+
 func (s *Session) assignSpecial(k string, v string) bool {
 	if isUnit(k) {
 		s.DefaultUnit = v
 		return true
 	}
+	if isTitle(k) {
+		s.Title = v
+		return true
+	}
 	return false
 }
@@ -2,6 +2,8 @@ package traindown
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -65,3 +67,536 @@ func TestAssignSpecialToSession(t *testing.T) {
 		}
 	}
 }
+
+func TestComputePercentagesForSession(t *testing.T) {
+	s := NewSession()
+	m := NewMovement()
+	p1 := &Performance{Load: 50.0}
+	p2 := &Performance{Load: 200.0}
+	m.Performances = []*Performance{p1, p2}
+	s.Movements = []*Movement{m}
+
+	s.ComputePercentages()
+
+	if p1.PercentOfMax != 25 || p2.PercentOfMax != 100 {
+		t.Errorf("Incorrect percentages: %v, %v", p1.PercentOfMax, p2.PercentOfMax)
+	}
+}
+
+func TestVolumeForSession(t *testing.T) {
+	s := NewSession()
+
+	v, err := s.Volume()
+
+	if v != 0 || err != nil {
+		t.Errorf("Expected zero volume for empty session, got %v (%v)", v, err)
+	}
+
+	m := NewMovement()
+	m.Performances = []*Performance{&Performance{Unit: "kg", Load: 100.0, Reps: 10, Sets: 1}}
+	s.Movements = []*Movement{m, m}
+
+	v, err = s.Volume()
+
+	if err != nil || v != 2000 {
+		t.Errorf("Expected 2000 without error, got %v (%v)", v, err)
+	}
+}
+
+func TestFindMovements(t *testing.T) {
+	s := NewSession()
+	squat := &Movement{Name: "Squat "}
+	front := &Movement{Name: "Front Squat"}
+	again := &Movement{Name: "squat"}
+	s.Movements = []*Movement{squat, front, again}
+
+	m, ok := s.FindMovement(" SQUAT")
+	if !ok || m != squat {
+		t.Errorf("Failed to find movement: %v", m)
+	}
+
+	if _, ok = s.FindMovement("bench"); ok {
+		t.Errorf("Found a missing movement")
+	}
+
+	ms := s.MovementsByName("squat")
+	if len(ms) != 2 || ms[0] != squat || ms[1] != again {
+		t.Errorf("Incorrect movements by name: %v", ms)
+	}
+
+	ms = s.MovementsMatching("squat")
+	if len(ms) != 3 {
+		t.Errorf("Incorrect matching movements: %v", ms)
+	}
+
+	if ms = s.MovementsByName("bench"); len(ms) != 0 {
+		t.Errorf("Incorrect movements by name: %v", ms)
+	}
+}
+
+func TestNormalizeUnits(t *testing.T) {
+	s := NewSession()
+	m := NewMovement()
+	p1 := &Performance{Unit: "kg", Load: 100.0}
+	p2 := &Performance{Unit: "lb", Load: 225.0}
+	p3 := NewPerformance()
+	m.Performances = []*Performance{p1, p2, p3}
+	s.Movements = []*Movement{m}
+
+	if err := s.NormalizeUnits("lb"); err != nil {
+		t.Fatalf("Failed to normalize: %v", err)
+	}
+
+	if p1.Load != 220.462 || p1.Unit != "lb" || p2.Load != 225 || p2.Unit != "lb" || p3.Unit != "unknown unit" {
+		t.Errorf("Incorrect normalization: %v, %v, %v", p1, p2, p3)
+	}
+
+	p2.Unit = "stone"
+
+	if err := s.NormalizeUnits("kg"); err == nil {
+		t.Errorf("Expected an error for an unknown unit")
+	}
+
+	if p1.Unit != "lb" || p1.Load != 220.462 {
+		t.Errorf("Failed normalization modified the session: %v", p1)
+	}
+
+	if err := s.NormalizeUnits("stone"); err == nil {
+		t.Errorf("Expected an error for an unknown target unit")
+	}
+}
+
+func TestNormalizeUnitsRange(t *testing.T) {
+	session, err := ParseString("@ 2021-03-04\n# unit: kg\nsquat:\n  100-110 3r\n  60 + 20kg collars 1r\n")
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if err := session.NormalizeUnits("lb"); err != nil {
+		t.Fatalf("Failed to normalize: %v", err)
+	}
+
+	ps := session.Movements[0].Performances
+
+	if !floatEqual(ps[0].Load, 220.462) || !floatEqual(ps[0].LoadMin, 220.462) || !floatEqual(ps[0].LoadMax, 242.508) {
+		t.Errorf("Failed to normalize the load range: %v", ps[0])
+	}
+
+	want := []LoadComponent{{132.277, "lb", ""}, {44.092, "lb", "collars"}}
+	if !floatEqual(ps[1].Load, 176.370) || !componentsEqual(ps[1].LoadComponents, want) {
+		t.Errorf("Failed to normalize the load components: %v", ps[1])
+	}
+
+	b, err := session.Marshal()
+
+	if err != nil {
+		t.Fatalf("Failed to marshal: %q", err)
+	}
+
+	if !strings.Contains(string(b), "220.462-242.508") {
+		t.Errorf("Expected the converted range in:\n%s", b)
+	}
+}
+
+func TestSuperSetGroups(t *testing.T) {
+	s := NewSession()
+
+	if groups := s.SuperSetGroups(); len(groups) != 0 {
+		t.Errorf("Expected no groups, got %v", groups)
+	}
+
+	squat := &Movement{Name: "squat"}
+	bench := &Movement{Name: "bench"}
+	row := &Movement{Name: "row", SuperSet: true}
+	curl := &Movement{Name: "curl", SuperSet: true}
+	dip := &Movement{Name: "dip"}
+	s.Movements = []*Movement{squat, bench, row, curl, dip}
+
+	groups := s.SuperSetGroups()
+
+	if len(groups) != 3 ||
+		len(groups[0]) != 1 || groups[0][0] != squat ||
+		len(groups[1]) != 3 || groups[1][0] != bench || groups[1][1] != row || groups[1][2] != curl ||
+		len(groups[2]) != 1 || groups[2][0] != dip {
+		t.Errorf("Incorrect groups: %v", groups)
+	}
+}
+
+func TestSuperSetGroupsByTag(t *testing.T) {
+	session, err := ParseString(`
+    bench:
+      # ss: A
+      100 5r
+    + fly:
+      20 10r
+    squat:
+      140 5r
+    row:
+      # SuperSet: A
+      80 8r
+    + curl:
+      # ss: B
+      15 10r
+    + dip:
+      bw 10r
+    plank:
+      bw 1r`)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	names := func(ms []*Movement) string {
+		var n []string
+		for _, m := range ms {
+			n = append(n, m.Name)
+		}
+		return strings.Join(n, ",")
+	}
+
+	groups := session.SuperSetGroupsByTag()
+
+	if len(groups) != 2 || names(groups["A"]) != "bench,fly,row" || names(groups["B"]) != "curl,dip" {
+		t.Errorf("Unexpected groups: %v", groups)
+	}
+
+	if groups := NewSession().SuperSetGroupsByTag(); len(groups) != 0 {
+		t.Errorf("Expected no groups, got %v", groups)
+	}
+}
+
+func TestSort(t *testing.T) {
+	s := NewSession()
+	m1 := &Movement{Name: "one", Sequence: 1}
+	m2 := &Movement{Name: "two", Sequence: 2}
+	m3 := &Movement{Name: "three", Sequence: 3}
+	p1 := &Performance{Load: 1, Sequence: 1}
+	p2 := &Performance{Load: 2, Sequence: 2}
+	m2.Performances = []*Performance{p2, p1}
+	s.Movements = []*Movement{m3, m1, m2}
+
+	s.Sort()
+
+	if s.Movements[0] != m1 || s.Movements[1] != m2 || s.Movements[2] != m3 {
+		t.Errorf("Failed to sort movements: %v", s.Movements)
+	}
+
+	if m2.Performances[0] != p1 || m2.Performances[1] != p2 {
+		t.Errorf("Failed to sort performances: %v", m2.Performances)
+	}
+
+	a := &Movement{Name: "a"}
+	b := &Movement{Name: "b"}
+	c := &Movement{Name: "c"}
+	s.Movements = []*Movement{b, c, a}
+
+	s.Sort()
+
+	if s.Movements[0] != b || s.Movements[1] != c || s.Movements[2] != a {
+		t.Errorf("Unsequenced movements were reordered: %v", s.Movements)
+	}
+}
+
+func TestSparseSessionJSON(t *testing.T) {
+	s := NewSession()
+	m := NewMovement()
+	m.Name = "squat"
+	p := NewPerformance()
+	p.Load = 100
+	p.Unit = "kg"
+	m.Performances = []*Performance{p}
+	s.Movements = []*Movement{m}
+
+	b, err := json.Marshal(s)
+
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+
+	expect := `{"date":"0001-01-01T00:00:00Z","movements":[{"name":"squat","sequence":0,"performances":[{"load":100,"reps":1,"sequence":0,"sets":1,"unit":"kg"}]}]}`
+
+	if string(b) != expect {
+		t.Errorf("Unexpected JSON:\n%s", b)
+	}
+}
+
+func TestAverageIntensity(t *testing.T) {
+	if i := NewSession().AverageIntensity(); i != 0 {
+		t.Errorf("Expected 0 for an empty session, got %v", i)
+	}
+
+	session, err := ParseString(`
+    squat:
+      200 1r
+      100 2r 2s
+      0 10r
+
+    bench:
+      80% 5r`)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	// (100*1 + 50*4 + 80*5) / 10
+	if i := session.AverageIntensity(); i != 70 {
+		t.Errorf("Expected an average intensity of 70, got %v", i)
+	}
+
+	if p := session.Movements[0].Performances[1]; p.PercentOfMax != 0 {
+		t.Errorf("AverageIntensity modified the session: %v", p)
+	}
+}
+
+func TestEachPerformance(t *testing.T) {
+	session, err := ParseString("squat:\n  100\n  200\n\nbench:\n  300")
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	var names []string
+	var loads []float32
+	session.EachPerformance(func(m *Movement, p *Performance) {
+		if m.Performances[p.Sequence-1] != p {
+			t.Errorf("Performance %v not owned by %q", p, m.Name)
+		}
+		names = append(names, m.Name)
+		loads = append(loads, p.Load)
+	})
+
+	if len(loads) != 3 || loads[0] != 100 || loads[2] != 300 || names[1] != "squat" || names[2] != "bench" {
+		t.Errorf("Unexpected traversal: %v %v", names, loads)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	session, err := ParseString("squat:\n  100\n  200\n    - 5r\n\nbench:\n  300\n\nrow:\n  50")
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	var visited []string
+	session.Walk(func(node interface{}) bool {
+		switch n := node.(type) {
+		case *Session:
+			visited = append(visited, "session")
+		case *Movement:
+			visited = append(visited, n.Name)
+		case *Performance:
+			visited = append(visited, fmt.Sprint(n.Load))
+		case *Set:
+			visited = append(visited, fmt.Sprintf("%dr", n.Reps))
+		}
+		return true
+	})
+
+	if strings.Join(visited, " ") != "session squat 100 200 5r bench 300 row 50" {
+		t.Errorf("Unexpected visit order: %v", visited)
+	}
+
+	var found *Movement
+	visited = nil
+	session.Walk(func(node interface{}) bool {
+		if found != nil {
+			return false
+		}
+		if m, ok := node.(*Movement); ok {
+			visited = append(visited, m.Name)
+			if m.Name == "bench" {
+				found = m
+				return false
+			}
+		}
+		return true
+	})
+
+	if found == nil || strings.Join(visited, " ") != "squat bench" {
+		t.Errorf("Failed to prune after bench: %v", visited)
+	}
+}
+
+func TestRoundLoads(t *testing.T) {
+	session, err := ParseString("# unit: kg\nsquat:\n  102.1 5r\n  100 5r")
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if err = session.NormalizeUnits(Pounds); err != nil {
+		t.Fatalf("Failed to normalize: %q", err)
+	}
+
+	ps := session.Movements[0].Performances
+
+	if ps[0].Load == 225.1 {
+		t.Fatalf("Expected a float32 artifact, got %v", ps[0].Load)
+	}
+
+	session.RoundLoads(1)
+
+	if ps[0].Load != 225.1 || ps[1].Load != 220.5 {
+		t.Errorf("Failed to round loads: %v %v", ps[0].Load, ps[1].Load)
+	}
+
+	session.RoundLoads(0)
+
+	if ps[0].Load != 225 || ps[1].Load != 221 {
+		t.Errorf("Failed to round loads: %v %v", ps[0].Load, ps[1].Load)
+	}
+}
+
+func TestNotesText(t *testing.T) {
+	if s := (Session{}).NotesText("; "); s != "" {
+		t.Errorf("Expected an empty string for nil notes, got %q", s)
+	}
+
+	s := Session{Notes: []string{"one", "", "  ", "two"}}
+
+	if got := s.NotesText("; "); got != "one; two" {
+		t.Errorf("Unexpected session notes text: %q", got)
+	}
+
+	m := Movement{Notes: []string{"", "only"}}
+
+	if got := m.NotesText("\n"); got != "only" {
+		t.Errorf("Unexpected movement notes text: %q", got)
+	}
+
+	p := Performance{Notes: []string{"a", "b"}}
+
+	if got := p.NotesText(", "); got != "a, b" {
+		t.Errorf("Unexpected performance notes text: %q", got)
+	}
+}
+
+func TestResolvePercents(t *testing.T) {
+	session, err := ParseString(`
+    squat:
+      80% 5r
+      90% 3r
+      200 1r
+
+    bench:
+      75% 5r
+
+    row:
+      70% 8r`)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	session.ResolvePercents(map[string]float32{"Squat": 250, "bench": 100})
+
+	squat := session.Movements[0].Performances
+
+	if squat[0].Load != 200 || squat[1].Load != 225 || squat[2].Load != 200 {
+		t.Errorf("Failed to resolve squat: %v", session.Movements[0])
+	}
+
+	if session.Movements[1].Performances[0].Load != 75 {
+		t.Errorf("Failed to resolve bench: %v", session.Movements[1])
+	}
+
+	if session.Movements[2].Performances[0].Load != 0 {
+		t.Errorf("Expected row to stay unresolved: %v", session.Movements[2])
+	}
+
+	if len(session.Errors) != 1 || session.Errors[0].Error() != `No max for "row"` {
+		t.Errorf("Expected a missing max error, got %v", session.Errors)
+	}
+}
+
+func TestMovementsByMuscle(t *testing.T) {
+	session, err := ParseString(`
+    bench:
+      # muscles: Chest, triceps,
+      100 5r
+
+    dip:
+      # Muscles: triceps
+      bw 10r
+
+    curl:
+      20 10r`)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if m := session.Movements[0].Muscles(); len(m) != 2 || m[0] != "Chest" || m[1] != "triceps" {
+		t.Errorf("Unexpected muscles: %v", m)
+	}
+
+	if m := session.Movements[2].Muscles(); m != nil {
+		t.Errorf("Expected no muscles, got %v", m)
+	}
+
+	if ms := session.MovementsByMuscle(" TRICEPS "); len(ms) != 2 || ms[0].Name != "bench" || ms[1].Name != "dip" {
+		t.Errorf("Unexpected triceps movements: %v", ms)
+	}
+
+	if ms := session.MovementsByMuscle("chest"); len(ms) != 1 {
+		t.Errorf("Unexpected chest movements: %v", ms)
+	}
+
+	if ms := session.MovementsByMuscle("quads"); len(ms) != 0 {
+		t.Errorf("Unexpected quads movements: %v", ms)
+	}
+}
+
+func TestSessionUnit(t *testing.T) {
+	cases := map[string]string{
+		"# unit: kg\n": "kg",
+		"# UNIT: lb\n": "lb",
+		"# U: kg\n":    "kg",
+		"# mood: ok\n": "",
+	}
+
+	for meta, want := range cases {
+		session, err := ParseString("@ 2021-03-04\n" + meta + "squat:\n  100 5r\n")
+
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %q", meta, err)
+		}
+
+		u, ok := session.Unit()
+		if u != want || ok != (want != "") {
+			t.Errorf("Unexpected unit for %q: %q %v", meta, u, ok)
+		}
+	}
+}
+
+func TestSessionTotalRepsAndSets(t *testing.T) {
+	session, err := ParseString(`@ 2021-03-04
+squat:
+  225 5r 3s
+  245 3r
+  255 2r 1f 2s
+
+bench:
+  135 10,8,6r
+  155
+    - 5r
+    - 3r 1f
+`)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if r := session.TotalReps(); r != 15+3+4+24+8 {
+		t.Errorf("Unexpected total reps: %d", r)
+	}
+
+	if s := session.TotalSets(); s != 3+1+2+3+2 {
+		t.Errorf("Unexpected total sets: %d", s)
+	}
+
+	if r := (&Session{}).TotalReps(); r != 0 {
+		t.Errorf("Unexpected total reps of an empty session: %d", r)
+	}
+}
@@ -0,0 +1,49 @@
+package traindown
+
+// Set is one set of a Performance, written on its own line after a "-" set
+// marker so it can carry its own reps, fails, metadata, and notes:
+//
+//	225 3s
+//	  - 5r
+//	    # rpe: 8
+//	  - 3r 2f
+//	    * grindy
+//
+// A Performance with such sets lists them in SetDetails. Its Sets is then the
+// number of sets listed, PerSetReps their reps, and Reps the most reps in a
+// set. A set without reps repeats the Reps of its Performance. Performances
+// without set markers leave SetDetails empty and keep their usual shape.
+type Set struct {
+	Fails int `json:"fails,omitempty"`
+	Reps  int `json:"reps"`
+
+	Metadata Metadata `json:"metadata,omitempty"`
+	Notes    []string `json:"notes,omitempty"`
+}
+
+// Clone returns a deep copy of the Set.
+func (s *Set) Clone() *Set {
+	c := *s
+	c.Metadata = s.Metadata.clone()
+	c.Notes = cloneNotes(s.Notes)
+	return &c
+}
+
+/* Private */
+
+// collapseSets derives Sets, PerSetReps, and Reps from the SetDetails.
+func (p *Performance) collapseSets() {
+	if len(p.SetDetails) == 0 {
+		return
+	}
+
+	p.Sets = len(p.SetDetails)
+	p.PerSetReps = make([]int, len(p.SetDetails))
+	p.Reps = 0
+	for i, s := range p.SetDetails {
+		p.PerSetReps[i] = s.Reps
+		if s.Reps > p.Reps {
+			p.Reps = s.Reps
+		}
+	}
+}
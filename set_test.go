@@ -0,0 +1,115 @@
+package traindown
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSets(t *testing.T) {
+	session, err := ParseString(`
+    squat:
+      225 5r
+        # belt: yes
+        -
+          # rpe: 7
+        - 5r
+          # rpe: 8
+        - 3r 2f
+          * grindy
+      135 5r 2s`)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if len(session.Errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", session.Errors)
+	}
+
+	ps := session.Movements[0].Performances
+
+	if len(ps) != 2 {
+		t.Fatalf("Expected 2 performances, got %v", ps)
+	}
+
+	p := ps[0]
+
+	if p.Sets != 3 || p.Reps != 5 || !reflect.DeepEqual(p.PerSetReps, []int{5, 5, 3}) || p.Metadata["belt"] != "yes" {
+		t.Errorf("Failed to collapse sets: %v", p)
+	}
+
+	expected := []*Set{
+		&Set{Reps: 5, Metadata: Metadata{"rpe": "7"}},
+		&Set{Reps: 5, Metadata: Metadata{"rpe": "8"}},
+		&Set{Reps: 3, Fails: 2, Notes: []string{"grindy"}},
+	}
+
+	if !reflect.DeepEqual(p.SetDetails, expected) {
+		t.Errorf("Unexpected sets: %v", p)
+	}
+
+	if v, _ := p.Volume(); v != 225*11 {
+		t.Errorf("Unexpected volume: %v", v)
+	}
+
+	if ps[1].SetDetails != nil || ps[1].Sets != 2 {
+		t.Errorf("Expected the usual shape without set markers: %v", ps[1])
+	}
+
+	b, err := session.Marshal()
+
+	if err != nil {
+		t.Fatalf("Failed to marshal: %q", err)
+	}
+
+	again, err := ParseByte(b)
+
+	if err != nil {
+		t.Fatalf("Failed to parse marshaled session: %q", err)
+	}
+
+	if !reflect.DeepEqual(session, again) {
+		t.Errorf("Round trip mismatch.\n\nGot:\n%v\n\nExpected:\n%v\n\n%s", again, session, b)
+	}
+
+	c := p.Clone()
+	c.SetDetails[0].Metadata["rpe"] = "10"
+
+	if p.SetDetails[0].Metadata["rpe"] != "7" {
+		t.Error("Clone shares set metadata")
+	}
+}
+
+func TestParseSetBeforePerformance(t *testing.T) {
+	session, err := ParseString("squat:\n  - 5r\n  100 5r")
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if len(session.Errors) != 1 || session.Errors[0].(*ParseError).Msg != "Set before any performance" {
+		t.Errorf("Expected an error, got %v", session.Errors)
+	}
+}
+
+func TestParseSetBulletOnlyStartsALine(t *testing.T) {
+	for _, txt := range []string{
+		"squat:\n  100 -3r\n",
+		"squat:\n  100 5r - 3s\n",
+		"squat:\n  -100 5r\n",
+	} {
+		if _, err := ParseString(txt); err == nil {
+			t.Errorf("Expected a lexer error for %q", txt)
+		}
+	}
+
+	session, err := ParseString("squat:\n  100 5r 3s\n  -\n\t- 4r\n-")
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if p := session.Movements[0].Performances[0]; len(p.SetDetails) != 3 {
+		t.Errorf("Expected 3 set details, got %v", p)
+	}
+}
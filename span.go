@@ -0,0 +1,27 @@
+package traindown
+
+// SourceSpan locates a Movement or Performance in the parsed source. Bytes
+// are offsets with the end exclusive, and lines are 1-based and inclusive.
+type SourceSpan struct {
+	StartByte int `json:"startByte"`
+	EndByte   int `json:"endByte"`
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine"`
+}
+
+/* Private */
+
+func (s *SourceSpan) extend(o SourceSpan) *SourceSpan {
+	if s == nil {
+		return &o
+	}
+
+	e := *s
+	if o.StartByte < e.StartByte {
+		e.StartByte, e.StartLine = o.StartByte, o.StartLine
+	}
+	if o.EndByte > e.EndByte {
+		e.EndByte, e.EndLine = o.EndByte, o.EndLine
+	}
+	return &e
+}
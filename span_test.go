@@ -0,0 +1,59 @@
+package traindown
+
+import (
+	"testing"
+)
+
+func TestExtendSpan(t *testing.T) {
+	var s *SourceSpan
+
+	s = s.extend(SourceSpan{10, 20, 2, 2})
+	s = s.extend(SourceSpan{25, 30, 3, 4})
+	s = s.extend(SourceSpan{5, 8, 1, 1})
+
+	if *s != (SourceSpan{5, 30, 1, 4}) {
+		t.Errorf("Incorrect span: %+v", s)
+	}
+}
+
+func TestParseSourceSpans(t *testing.T) {
+	text := "@ 2020-01-01\nsquat:\n  * deep\n  100 5r\n    # rpe: 8\n  200\n\nbench:\n  50"
+
+	session, err := ParseStringWithOptions(text, WithSourceSpans(true))
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	squat := session.Movements[0]
+	bench := session.Movements[1]
+
+	spans := map[string]*SourceSpan{
+		"squat:\n  * deep\n  100 5r\n    # rpe: 8\n  200": squat.Span,
+		"100 5r\n    # rpe: 8":                            squat.Performances[0].Span,
+		"200":                                             squat.Performances[1].Span,
+		"bench:\n  50":                                    bench.Span,
+		"50":                                              bench.Performances[0].Span,
+	}
+
+	for want, span := range spans {
+		if span == nil {
+			t.Errorf("Missing span for %q", want)
+			continue
+		}
+
+		if got := text[span.StartByte:span.EndByte]; got != want {
+			t.Errorf("Incorrect span: %q, expected %q", got, want)
+		}
+	}
+
+	if squat.Span.StartLine != 2 || squat.Span.EndLine != 6 {
+		t.Errorf("Incorrect lines: %+v", squat.Span)
+	}
+
+	session, err = ParseString(text)
+
+	if err != nil || session.Movements[0].Span != nil || session.Movements[0].Performances[0].Span != nil {
+		t.Errorf("Recorded spans without the option")
+	}
+}
@@ -1,5 +1,9 @@
 package traindown
 
+import (
+	"strings"
+)
+
 // SpecialAssignable is an interface for things that make use of the IsUnit check.
 type specialAssignable interface {
 	assignSpecial(string string) bool
@@ -12,3 +16,8 @@ func isUnit(u string) bool {
 	}
 	return false
 }
+
+// isTitle returns true if the argument is the session title keyword.
+func isTitle(k string) bool {
+	return strings.EqualFold(k, "title")
+}
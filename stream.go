@@ -0,0 +1,56 @@
+package traindown
+
+// Scope is the element that metadata or a note belongs to.
+type Scope int
+
+// Scopes reported to a Handler.
+const (
+	SessionScope Scope = iota
+	MovementScope
+	PerformanceScope
+	SetScope
+)
+
+// Handler receives the events of ParseStreamString. Metadata and notes are
+// reported as they are read, and attach to the next element of their Scope
+// to be completed. Performances, movements, and sessions are reported once
+// complete, so a Movement arrives after its performances and a Session after
+// its movements. Returning an error stops the parse.
+type Handler interface {
+	OnSession(s *Session) error
+	OnMovement(m *Movement) error
+	OnPerformance(m *Movement, p *Performance) error
+	OnMetadata(scope Scope, key string, value string) error
+	OnNote(scope Scope, note string) error
+}
+
+// ParseStreamString parses txt as ParseSessions does, handing each element to
+// h instead of building the whole tree. Movements reported to h hold no
+// performances and sessions hold no movements, so options that work across a
+// whole Session, such as WithPercentages, have nothing to act on. Parse
+// errors are left on each Session, as WithStrict does not apply.
+func ParseStreamString(txt string, h Handler, opts ...Option) error {
+	tokens, err := scan([]byte(txt))
+
+	if err != nil {
+		return err
+	}
+
+	ps := newParser(true, newParseConfig(opts))
+	ps.collectAliases(tokens)
+	ps.h = h
+
+	for _, tok := range tokens {
+		ps.handle(tok)
+
+		if ps.herr != nil {
+			return ps.herr
+		}
+	}
+
+	if !isEmpty(tokens) {
+		ps.finish()
+	}
+
+	return ps.herr
+}
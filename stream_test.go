@@ -0,0 +1,97 @@
+package traindown
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type recorder struct {
+	events []string
+	stopAt string
+}
+
+func (r *recorder) record(e string) error {
+	r.events = append(r.events, e)
+	if e == r.stopAt {
+		return errors.New("stop")
+	}
+	return nil
+}
+
+func (r *recorder) OnSession(s *Session) error {
+	return r.record(fmt.Sprintf("session %s %d", s.Date.Format("2006-01-02"), len(s.Movements)))
+}
+
+func (r *recorder) OnMovement(m *Movement) error {
+	return r.record(fmt.Sprintf("movement %s %d %d", m.Name, m.Sequence, len(m.Performances)))
+}
+
+func (r *recorder) OnPerformance(m *Movement, p *Performance) error {
+	return r.record(fmt.Sprintf("performance %s %v %d", m.Name, p.Load, p.Sequence))
+}
+
+func (r *recorder) OnMetadata(scope Scope, key string, value string) error {
+	return r.record(fmt.Sprintf("metadata %d %s=%s", scope, key, value))
+}
+
+func (r *recorder) OnNote(scope Scope, note string) error {
+	return r.record(fmt.Sprintf("note %d %s", scope, note))
+}
+
+func TestParseStreamString(t *testing.T) {
+	text := `
+    @ 2020-01-01
+    # key: value
+
+    squat:
+      * deep
+      100 5r
+        # rpe: 8
+      200 5r
+
+    @ 2020-01-02
+
+    bench:
+      100`
+
+	r := &recorder{}
+
+	if err := ParseStreamString(text, r); err != nil {
+		t.Fatalf("Failed to stream: %q", err)
+	}
+
+	expected := []string{
+		"metadata 0 key=value",
+		"note 1 deep",
+		"metadata 2 rpe=8",
+		"performance squat 100 1",
+		"performance squat 200 2",
+		"movement squat 1 0",
+		"session 2020-01-01 0",
+		"performance bench 100 1",
+		"movement bench 1 0",
+		"session 2020-01-02 0",
+	}
+
+	if !reflect.DeepEqual(r.events, expected) {
+		t.Errorf("Unexpected events.\n\nGot:\n%q\n\nExpected:\n%q", r.events, expected)
+	}
+
+	r = &recorder{stopAt: "movement squat 1 0"}
+
+	if err := ParseStreamString(text, r); err == nil || err.Error() != "stop" {
+		t.Errorf("Expected the handler error, got %v", err)
+	}
+
+	if len(r.events) != 6 {
+		t.Errorf("Expected parsing to stop at the handler error, got %q", r.events)
+	}
+
+	r = &recorder{}
+
+	if err := ParseStreamString("  ", r); err != nil || len(r.events) != 0 {
+		t.Errorf("Expected no events for empty input: %v %q", err, r.events)
+	}
+}
@@ -0,0 +1,39 @@
+package traindown
+
+// SessionSummary holds totals for a Session.
+type SessionSummary struct {
+	Movements    int     `json:"movements"`
+	Performances int     `json:"performances"`
+	Sets         int     `json:"sets"`
+	Reps         int     `json:"reps"`
+	Volume       float32 `json:"volume"`
+
+	HeaviestLoad     float32 `json:"heaviestLoad"`
+	HeaviestMovement string  `json:"heaviestMovement"`
+}
+
+// Summary totals the Session. Reps are counted across every set, as with
+// TotalReps, so a performance of 5 reps for 3 sets adds 15. Volume sums across units; see
+// Volumes for a per unit breakdown.
+func (s Session) Summary() SessionSummary {
+	sum := SessionSummary{Movements: len(s.Movements)}
+
+	for _, m := range s.Movements {
+		sum.Performances += len(m.Performances)
+
+		for _, p := range m.Performances {
+			v, _ := p.Volume()
+
+			sum.Sets += p.Sets
+			sum.Reps += p.TotalReps()
+			sum.Volume += v
+
+			if p.Load > sum.HeaviestLoad {
+				sum.HeaviestLoad = p.Load
+				sum.HeaviestMovement = m.Name
+			}
+		}
+	}
+
+	return sum
+}
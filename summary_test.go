@@ -0,0 +1,57 @@
+package traindown
+
+import (
+	"testing"
+)
+
+func TestSummary(t *testing.T) {
+	text := `
+    squat:
+      100 5r 3s
+      200 1r
+    bench:
+      150 2r 2s`
+
+	session, err := ParseString(text)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	expected := SessionSummary{
+		Movements:        2,
+		Performances:     3,
+		Sets:             6,
+		Reps:             20,
+		Volume:           2300,
+		HeaviestLoad:     200,
+		HeaviestMovement: "squat",
+	}
+
+	if sum := session.Summary(); sum != expected {
+		t.Errorf("Incorrect summary: %+v", sum)
+	}
+}
+
+func TestSummaryEmpty(t *testing.T) {
+	if sum := NewSession().Summary(); sum != (SessionSummary{}) {
+		t.Errorf("Expected an empty summary, got %+v", sum)
+	}
+}
+
+func TestSummaryPerSetReps(t *testing.T) {
+	session, err := ParseString(`
+    squat:
+      100 10,8,6r
+      200
+        - 3r
+        - 2r 1f`)
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if sum := session.Summary(); sum.Sets != 5 || sum.Reps != 24+5 {
+		t.Errorf("Incorrect summary: %+v", sum)
+	}
+}
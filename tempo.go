@@ -0,0 +1,52 @@
+package traindown
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Tempo is the cadence of a rep in seconds, as in "3-1-2-0". An X, meaning
+// explosive, is recorded as 0.
+type Tempo struct {
+	Eccentric   int `json:"eccentric"`
+	BottomPause int `json:"bottomPause"`
+	Concentric  int `json:"concentric"`
+	TopPause    int `json:"topPause"`
+}
+
+// ParseTempo reads a tempo written either dash separated ("3-1-X-0") or as
+// four single digits ("31X0").
+func ParseTempo(s string) (Tempo, error) {
+	s = strings.TrimSpace(s)
+
+	var parts []string
+	if strings.Contains(s, "-") {
+		parts = strings.Split(s, "-")
+	} else {
+		parts = strings.Split(s, "")
+	}
+
+	if len(parts) != 4 {
+		return Tempo{}, fmt.Errorf("Failed to parse %q: %q", "tempo", s)
+	}
+
+	var phases [4]int
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+
+		if part == "x" || part == "X" {
+			continue
+		}
+
+		n, err := strconv.Atoi(part)
+
+		if err != nil || n < 0 {
+			return Tempo{}, fmt.Errorf("Failed to parse %q: %q", "tempo", s)
+		}
+
+		phases[i] = n
+	}
+
+	return Tempo{phases[0], phases[1], phases[2], phases[3]}, nil
+}
@@ -0,0 +1,29 @@
+package traindown
+
+import (
+	"testing"
+)
+
+func TestParseTempo(t *testing.T) {
+	valid := map[string]Tempo{
+		"3-1-2-0":   Tempo{3, 1, 2, 0},
+		"3-1-X-0":   Tempo{3, 1, 0, 0},
+		"3010":      Tempo{3, 0, 1, 0},
+		"40x1":      Tempo{4, 0, 0, 1},
+		" 10-0-1-0": Tempo{10, 0, 1, 0},
+	}
+
+	for s, want := range valid {
+		got, err := ParseTempo(s)
+
+		if err != nil || got != want {
+			t.Errorf("Failed to parse %q: %v (%v)", s, got, err)
+		}
+	}
+
+	for _, s := range []string{"", "3-1-2", "301", "3-1-2-0-1", "3-a-2-0", "3-1--1-0"} {
+		if _, err := ParseTempo(s); err == nil {
+			t.Errorf("Expected an error for %q", s)
+		}
+	}
+}
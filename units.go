@@ -0,0 +1,56 @@
+package traindown
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PoundsPerKilogram is the factor used when converting between kg and lb.
+const PoundsPerKilogram = 2.20462
+
+// Canonical unit names returned by CanonicalUnit.
+const (
+	Kilograms = "kg"
+	Pounds    = "lb"
+)
+
+var unitAliases = map[string]string{
+	"kg":        Kilograms,
+	"kgs":       Kilograms,
+	"kilo":      Kilograms,
+	"kilos":     Kilograms,
+	"kilogram":  Kilograms,
+	"kilograms": Kilograms,
+	"lb":        Pounds,
+	"lbs":       Pounds,
+	"pound":     Pounds,
+	"pounds":    Pounds,
+}
+
+// CanonicalUnit maps a unit spelling such as "Kilos" or "lbs" to kg or lb.
+func CanonicalUnit(u string) (string, bool) {
+	c, ok := unitAliases[strings.ToLower(strings.TrimSpace(u))]
+	return c, ok
+}
+
+// ConvertLoad converts a load between two convertible units.
+func ConvertLoad(load float32, from string, to string) (float32, error) {
+	f, ok := CanonicalUnit(from)
+	if !ok {
+		return 0, fmt.Errorf("Unknown unit: %q", from)
+	}
+
+	t, ok := CanonicalUnit(to)
+	if !ok {
+		return 0, fmt.Errorf("Unknown unit: %q", to)
+	}
+
+	switch {
+	case f == t:
+		return load, nil
+	case f == Kilograms:
+		return float32(float64(load) * PoundsPerKilogram), nil
+	default:
+		return float32(float64(load) / PoundsPerKilogram), nil
+	}
+}
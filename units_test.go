@@ -0,0 +1,39 @@
+package traindown
+
+import (
+	"testing"
+)
+
+func TestCanonicalUnit(t *testing.T) {
+	for u, want := range map[string]string{"KG": Kilograms, " kilos": Kilograms, "lbs": Pounds, "Pound": Pounds} {
+		if got, ok := CanonicalUnit(u); !ok || got != want {
+			t.Errorf("Incorrect canonical unit for %q: %q", u, got)
+		}
+	}
+
+	if _, ok := CanonicalUnit("your mom"); ok {
+		t.Errorf("Recognized an unknown unit")
+	}
+}
+
+func TestConvertLoad(t *testing.T) {
+	if l, err := ConvertLoad(100, "kg", "lbs"); err != nil || l != 220.462 {
+		t.Errorf("Failed to convert kg to lb: %v (%v)", l, err)
+	}
+
+	if l, err := ConvertLoad(220.462, "lb", "kg"); err != nil || l != 100 {
+		t.Errorf("Failed to convert lb to kg: %v (%v)", l, err)
+	}
+
+	if l, err := ConvertLoad(100, "kilos", "kg"); err != nil || l != 100 {
+		t.Errorf("Failed to convert kg to kg: %v (%v)", l, err)
+	}
+
+	if _, err := ConvertLoad(100, "stone", "kg"); err == nil {
+		t.Errorf("Expected an error for an unknown unit")
+	}
+
+	if _, err := ConvertLoad(100, "kg", "stone"); err == nil {
+		t.Errorf("Expected an error for an unknown unit")
+	}
+}
@@ -0,0 +1,109 @@
+package traindown
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationRule inspects a Session and reports any problems it finds. Rules
+// must not modify the Session. The rules here report a ParseError at the
+// first line of the offending Movement or Performance when source spans were
+// recorded; see WithSourceSpans.
+type ValidationRule func(s *Session) []error
+
+// DefaultRules are the rules applied by Validate.
+var DefaultRules = []ValidationRule{
+	NoNegativeValues,
+	NoUnrepeatedLoads,
+	NoDuplicateMovements,
+}
+
+// MaxLoad is the heaviest Load NoAbsurdLoads accepts.
+const MaxLoad = 10000
+
+// Validate checks the Session against DefaultRules.
+func (s *Session) Validate() []error {
+	return s.ValidateWith(DefaultRules...)
+}
+
+// ValidateWith checks the Session against the given rules, in order.
+func (s *Session) ValidateWith(rules ...ValidationRule) []error {
+	var errs []error
+	for _, rule := range rules {
+		errs = append(errs, rule(s)...)
+	}
+	return errs
+}
+
+// NoNegativeValues flags performances with a negative load, reps, or sets.
+func NoNegativeValues(s *Session) []error {
+	var errs []error
+	for _, m := range s.Movements {
+		for _, p := range m.Performances {
+			if p.Load < 0 {
+				errs = append(errs, issue(p.Span, "%s #%d: Negative load: %v", m.Name, p.Sequence, p.Load))
+			}
+			if p.Reps < 0 {
+				errs = append(errs, issue(p.Span, "%s #%d: Negative reps: %d", m.Name, p.Sequence, p.Reps))
+			}
+			if p.Sets < 0 {
+				errs = append(errs, issue(p.Span, "%s #%d: Negative sets: %d", m.Name, p.Sequence, p.Sets))
+			}
+		}
+	}
+	return errs
+}
+
+// NoUnrepeatedLoads flags loaded performances with zero reps.
+func NoUnrepeatedLoads(s *Session) []error {
+	var errs []error
+	for _, m := range s.Movements {
+		for _, p := range m.Performances {
+			if p.Load > 0 && p.Reps == 0 {
+				errs = append(errs, issue(p.Span, "%s #%d: Zero reps at %v", m.Name, p.Sequence, p.Load))
+			}
+		}
+	}
+	return errs
+}
+
+// NoDuplicateMovements flags movements whose name, ignoring case, appears
+// more than once.
+func NoDuplicateMovements(s *Session) []error {
+	var errs []error
+	seen := make(map[string]bool)
+	for _, m := range s.Movements {
+		k := strings.ToLower(strings.TrimSpace(m.Name))
+		if seen[k] {
+			errs = append(errs, issue(m.Span, "Duplicate movement: %q", m.Name))
+		}
+		seen[k] = true
+	}
+	return errs
+}
+
+// NoAbsurdLoads flags performances with a Load over MaxLoad, which no one
+// lifts in any unit.
+func NoAbsurdLoads(s *Session) []error {
+	var errs []error
+	for _, m := range s.Movements {
+		for _, p := range m.Performances {
+			if p.Load > MaxLoad {
+				errs = append(errs, issue(p.Span, "%s #%d: Absurd load: %v", m.Name, p.Sequence, p.Load))
+			}
+		}
+	}
+	return errs
+}
+
+/* Private */
+
+// issue formats a validation error, placed at the start of span when there
+// is one.
+func issue(span *SourceSpan, format string, args ...interface{}) error {
+	err := fmt.Errorf(format, args...)
+	if span == nil {
+		return err
+	}
+	return &ParseError{Line: span.StartLine, Col: 1, Msg: err.Error()}
+}
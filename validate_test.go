@@ -0,0 +1,81 @@
+package traindown
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	session, err := ParseString("squat:\n  100 5r\n  200 0r\n  bench:\n  50\n  Squat:\n  60")
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	session.Movements[1].Performances[0].Load = -50
+	session.Movements[1].Performances[0].Sets = -1
+
+	errs := session.Validate()
+
+	expected := []string{
+		"bench #1: Negative load: -50",
+		"bench #1: Negative sets: -1",
+		"squat #2: Zero reps at 200",
+		`Duplicate movement: "Squat"`,
+	}
+
+	if len(errs) != len(expected) {
+		t.Fatalf("Expected %d errors, got %q", len(expected), errs)
+	}
+
+	for i, msg := range expected {
+		if errs[i].Error() != msg {
+			t.Errorf("Expected %q, got %q", msg, errs[i])
+		}
+	}
+
+	if session.Movements[1].Performances[0].Load != -50 {
+		t.Errorf("Validation modified the session")
+	}
+}
+
+func TestValidateWith(t *testing.T) {
+	session, err := ParseString("squat:\n  100 5r")
+
+	if err != nil {
+		t.Fatalf("Failed to parse: %q", err)
+	}
+
+	if errs := session.Validate(); len(errs) != 0 {
+		t.Errorf("Unexpected errors: %q", errs)
+	}
+
+	noSquats := func(s *Session) []error {
+		if _, ok := s.FindMovement("squat"); ok {
+			return []error{errors.New("No squats allowed")}
+		}
+		return nil
+	}
+
+	errs := session.ValidateWith(noSquats, NoNegativeValues)
+
+	if len(errs) != 1 || errs[0].Error() != "No squats allowed" {
+		t.Errorf("Incorrect errors: %q", errs)
+	}
+}
+
+func TestNoAbsurdLoads(t *testing.T) {
+	s, _ := NewSessionBuilder().
+		Movement("squat").
+		Perform(10000, 5, 1).
+		Perform(10001, -3, 1).
+		Build()
+
+	if errs := s.ValidateWith(NoAbsurdLoads); len(errs) != 1 || errs[0].Error() != "squat #2: Absurd load: 10001" {
+		t.Errorf("Unexpected errors: %v", errs)
+	}
+
+	if errs := s.ValidateWith(NoNegativeValues); len(errs) != 1 || errs[0].Error() != "squat #2: Negative reps: -3" {
+		t.Errorf("Unexpected errors: %v", errs)
+	}
+}